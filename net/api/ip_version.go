@@ -0,0 +1,30 @@
+package api
+
+import "fmt"
+
+// Address family numbers as used by BGP multiprotocol extensions (RFC4760)
+const (
+	afiIPv4 = 1
+	afiIPv6 = 2
+)
+
+// AFI gets the address family number of the IP version
+func (x IP_Version) AFI() uint16 {
+	if x == IP_IPv6 {
+		return afiIPv6
+	}
+
+	return afiIPv4
+}
+
+// IPVersionFromAFI gets the IP version of an address family number
+func IPVersionFromAFI(afi uint16) (IP_Version, error) {
+	switch afi {
+	case afiIPv4:
+		return IP_IPv4, nil
+	case afiIPv6:
+		return IP_IPv6, nil
+	}
+
+	return IP_IPv4, fmt.Errorf("unsupported AFI %d", afi)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPVersionAFI(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  IP_Version
+		afi      uint16
+		wantFail bool
+	}{
+		{
+			name:    "IPv4",
+			version: IP_IPv4,
+			afi:     1,
+		},
+		{
+			name:    "IPv6",
+			version: IP_IPv6,
+			afi:     2,
+		},
+		{
+			name:     "Invalid AFI",
+			afi:      25,
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := IPVersionFromAFI(test.afi)
+			if test.wantFail {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.version, v)
+			assert.Equal(t, test.afi, test.version.AFI())
+		})
+	}
+}
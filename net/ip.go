@@ -296,6 +296,27 @@ func (ip *IP) bitAtPositionIPv6(pos uint8) bool {
 	return (ip.lower & (1 << (128 - pos))) != 0
 }
 
+// Bit returns the bit at position pos with 0 being the most significant bit. Bits beyond the length of the address are 0.
+func (ip IP) Bit(pos uint8) uint8 {
+	if ip.isLegacy {
+		if pos >= 32 {
+			return 0
+		}
+
+		return uint8(ip.lower>>(31-pos)) & 1
+	}
+
+	if pos >= 128 {
+		return 0
+	}
+
+	if pos < 64 {
+		return uint8(ip.higher>>(63-pos)) & 1
+	}
+
+	return uint8(ip.lower>>(127-pos)) & 1
+}
+
 // Next gets the next ip address
 func (ip *IP) Next() *IP {
 	newIP := ip.copy()
@@ -340,3 +361,28 @@ func (ip *IP) maskLastNBitsIPv6(n uint8) {
 	ip.lower = ip.lower & maskLow
 	ip.higher = ip.higher & maskHigh
 }
+
+// Mask returns a copy of ip with all bits beyond length set to zero
+func (ip IP) Mask(length uint8) IP {
+	if ip.isLegacy {
+		if length >= 32 {
+			return ip
+		}
+
+		ip.lower = ip.lower & uint64(math.MaxUint32<<(32-length)) & math.MaxUint32
+		return ip
+	}
+
+	if length >= 128 {
+		return ip
+	}
+
+	if length <= 64 {
+		ip.lower = 0
+		ip.higher = ip.higher & ^(math.MaxUint64 >> length)
+		return ip
+	}
+
+	ip.lower = ip.lower & ^(math.MaxUint64 >> (length - 64))
+	return ip
+}
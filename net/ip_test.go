@@ -695,3 +695,121 @@ func TestIPv4FromBytes(t *testing.T) {
 	resultInvalid := IPv4FromBytes([]byte{1, 2, 3, 4, 5})
 	assert.Equal(t, expectedInvalid, resultInvalid)
 }
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    IP
+		length   uint8
+		expected IP
+	}{
+		{
+			name:     "IPv4 /25",
+			input:    IPv4FromOctets(192, 0, 2, 130),
+			length:   25,
+			expected: IPv4FromOctets(192, 0, 2, 128),
+		},
+		{
+			name:     "IPv4 /0",
+			input:    IPv4FromOctets(192, 0, 2, 130),
+			length:   0,
+			expected: IPv4FromOctets(0, 0, 0, 0),
+		},
+		{
+			name:     "IPv4 /32",
+			input:    IPv4FromOctets(192, 0, 2, 130),
+			length:   32,
+			expected: IPv4FromOctets(192, 0, 2, 130),
+		},
+		{
+			name:     "IPv6 /32",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0x1234, 0x2222, 0x1111, 0x3333, 0xbbbb, 0xacab),
+			length:   32,
+			expected: IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0),
+		},
+		{
+			name:     "IPv6 /72",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0x1234, 0x2222, 0x1111, 0x3333, 0xbbbb, 0xacab),
+			length:   72,
+			expected: IPv6FromBlocks(0x2001, 0xdb8, 0x1234, 0x2222, 0x1100, 0, 0, 0),
+		},
+		{
+			name:     "IPv6 /128",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0x1234, 0x2222, 0x1111, 0x3333, 0xbbbb, 0xacab),
+			length:   128,
+			expected: IPv6FromBlocks(0x2001, 0xdb8, 0x1234, 0x2222, 0x1111, 0x3333, 0xbbbb, 0xacab),
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.input.Mask(test.length), test.name)
+	}
+}
+
+func TestBit(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    IP
+		pos      uint8
+		expected uint8
+	}{
+		{
+			name:     "IPv4 most significant bit",
+			input:    IPv4FromOctets(192, 0, 2, 1),
+			pos:      0,
+			expected: 1,
+		},
+		{
+			name:     "IPv4 bit 2",
+			input:    IPv4FromOctets(192, 0, 2, 1),
+			pos:      2,
+			expected: 0,
+		},
+		{
+			name:     "IPv4 bit 22",
+			input:    IPv4FromOctets(192, 0, 2, 1),
+			pos:      22,
+			expected: 1,
+		},
+		{
+			name:     "IPv4 least significant bit",
+			input:    IPv4FromOctets(192, 0, 2, 1),
+			pos:      31,
+			expected: 1,
+		},
+		{
+			name:     "IPv4 beyond 32 bits",
+			input:    IPv4FromOctets(255, 255, 255, 255),
+			pos:      32,
+			expected: 0,
+		},
+		{
+			name:     "IPv6 bit 2",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1),
+			pos:      2,
+			expected: 1,
+		},
+		{
+			name:     "IPv6 bit 3",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1),
+			pos:      3,
+			expected: 0,
+		},
+		{
+			name:     "IPv6 bit 64",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0x8000, 0, 0, 1),
+			pos:      64,
+			expected: 1,
+		},
+		{
+			name:     "IPv6 least significant bit",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1),
+			pos:      127,
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.input.Bit(test.pos), test.name)
+	}
+}
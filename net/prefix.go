@@ -267,33 +267,6 @@ func checkLastNBitsUint64(x uint64, n uint8) bool {
 
 // BaseAddr gets the base address of the prefix
 func (p *Prefix) BaseAddr() *IP {
-	if p.addr.isLegacy {
-		return p.baseAddr4()
-	}
-
-	return p.baseAddr6()
-}
-
-func (p *Prefix) baseAddr4() *IP {
-	addr := p.addr.copy()
-
-	addr.lower = addr.lower >> (32 - p.pfxlen)
-	addr.lower = addr.lower << (32 - p.pfxlen)
-
-	return addr
-}
-
-func (p *Prefix) baseAddr6() *IP {
-	addr := p.addr.copy()
-
-	if p.pfxlen <= 64 {
-		addr.lower = 0
-		addr.higher = addr.higher >> (64 - p.pfxlen)
-		addr.higher = addr.higher << (64 - p.pfxlen)
-	} else {
-		addr.lower = addr.lower >> (128 - p.pfxlen)
-		addr.lower = addr.lower << (128 - p.pfxlen)
-	}
-
-	return addr
+	addr := p.addr.Mask(p.pfxlen)
+	return &addr
 }
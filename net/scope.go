@@ -0,0 +1,83 @@
+package net
+
+// Scope is the scope of an address
+type Scope uint8
+
+const (
+	// ScopeGlobal is the scope of globally routable addresses
+	ScopeGlobal Scope = iota
+	// ScopeLoopback is the scope of loopback addresses (127.0.0.0/8, ::1/128)
+	ScopeLoopback
+	// ScopeLinkLocal is the scope of link local addresses (169.254.0.0/16, fe80::/10)
+	ScopeLinkLocal
+	// ScopeUniqueLocal is the scope of unique local IPv6 addresses (fc00::/7)
+	ScopeUniqueLocal
+	// ScopeDocumentation is the scope of addresses reserved for documentation (RFC5737, RFC3849)
+	ScopeDocumentation
+)
+
+var (
+	scopePrefixes = []struct {
+		addr   IP
+		pfxlen uint8
+		scope  Scope
+	}{
+		{addr: IPv4FromOctets(127, 0, 0, 0), pfxlen: 8, scope: ScopeLoopback},
+		{addr: IPv4FromOctets(169, 254, 0, 0), pfxlen: 16, scope: ScopeLinkLocal},
+		{addr: IPv4FromOctets(192, 0, 2, 0), pfxlen: 24, scope: ScopeDocumentation},
+		{addr: IPv4FromOctets(198, 51, 100, 0), pfxlen: 24, scope: ScopeDocumentation},
+		{addr: IPv4FromOctets(203, 0, 113, 0), pfxlen: 24, scope: ScopeDocumentation},
+		{addr: IPv6FromBlocks(0, 0, 0, 0, 0, 0, 0, 1), pfxlen: 128, scope: ScopeLoopback},
+		{addr: IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 0), pfxlen: 10, scope: ScopeLinkLocal},
+		{addr: IPv6FromBlocks(0xfc00, 0, 0, 0, 0, 0, 0, 0), pfxlen: 7, scope: ScopeUniqueLocal},
+		{addr: IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), pfxlen: 32, scope: ScopeDocumentation},
+	}
+)
+
+// String returns the name of the scope
+func (s Scope) String() string {
+	switch s {
+	case ScopeGlobal:
+		return "global"
+	case ScopeLoopback:
+		return "loopback"
+	case ScopeLinkLocal:
+		return "link-local"
+	case ScopeUniqueLocal:
+		return "unique-local"
+	case ScopeDocumentation:
+		return "documentation"
+	}
+
+	return "unknown"
+}
+
+// Scope gets the scope of ip
+func (ip *IP) Scope() Scope {
+	for _, x := range scopePrefixes {
+		if x.addr.isLegacy != ip.isLegacy {
+			continue
+		}
+
+		if ip.Mask(x.pfxlen) == x.addr {
+			return x.scope
+		}
+	}
+
+	return ScopeGlobal
+}
+
+// Scope gets the scope of the prefix. A prefix only partially covering a scope is considered global.
+func (p *Prefix) Scope() Scope {
+	for _, x := range scopePrefixes {
+		if x.addr.isLegacy != p.addr.isLegacy || p.pfxlen < x.pfxlen {
+			continue
+		}
+
+		if p.addr.Mask(x.pfxlen) == x.addr {
+			return x.scope
+		}
+	}
+
+	return ScopeGlobal
+}
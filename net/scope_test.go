@@ -0,0 +1,93 @@
+package net
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    IP
+		expected Scope
+	}{
+		{
+			name:     "IPv4 global",
+			input:    IPv4FromOctets(185, 65, 240, 1),
+			expected: ScopeGlobal,
+		},
+		{
+			name:     "IPv4 loopback",
+			input:    IPv4FromOctets(127, 0, 0, 1),
+			expected: ScopeLoopback,
+		},
+		{
+			name:     "IPv4 link local",
+			input:    IPv4FromOctets(169, 254, 1, 1),
+			expected: ScopeLinkLocal,
+		},
+		{
+			name:     "IPv4 documentation",
+			input:    IPv4FromOctets(192, 0, 2, 1),
+			expected: ScopeDocumentation,
+		},
+		{
+			name:     "IPv6 global",
+			input:    IPv6FromBlocks(0x2a01, 0x4f8, 0, 0, 0, 0, 0, 1),
+			expected: ScopeGlobal,
+		},
+		{
+			name:     "IPv6 loopback",
+			input:    IPv6FromBlocks(0, 0, 0, 0, 0, 0, 0, 1),
+			expected: ScopeLoopback,
+		},
+		{
+			name:     "IPv6 link local",
+			input:    IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1),
+			expected: ScopeLinkLocal,
+		},
+		{
+			name:     "IPv6 ULA",
+			input:    IPv6FromBlocks(0xfd12, 0x3456, 0, 0, 0, 0, 0, 1),
+			expected: ScopeUniqueLocal,
+		},
+		{
+			name:     "IPv6 documentation",
+			input:    IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1),
+			expected: ScopeDocumentation,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.input.Scope(), test.name)
+	}
+}
+
+func TestPrefixScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Prefix
+		expected Scope
+	}{
+		{
+			name:     "Documentation prefix",
+			input:    NewPfx(IPv4FromOctets(192, 0, 2, 0), 25),
+			expected: ScopeDocumentation,
+		},
+		{
+			name:     "Covering prefix is global",
+			input:    NewPfx(IPv4FromOctets(192, 0, 0, 0), 16),
+			expected: ScopeGlobal,
+		},
+		{
+			name:     "IPv6 ULA prefix",
+			input:    NewPfx(IPv6FromBlocks(0xfd00, 0, 0, 0, 0, 0, 0, 0), 8),
+			expected: ScopeUniqueLocal,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.input.Scope(), test.name)
+	}
+}
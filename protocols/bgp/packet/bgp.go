@@ -1,5 +1,9 @@
 package packet
 
+import (
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
 const (
 	OctetLen       = 8
 	MaxASNsSegment = 255
@@ -65,19 +69,23 @@ const (
 	AdministrativeReset    = 4
 
 	// Attribute Type Codes
-	OriginAttr           = 1
-	ASPathAttr           = 2
-	NextHopAttr          = 3
-	MEDAttr              = 4
-	LocalPrefAttr        = 5
-	AtomicAggrAttr       = 6
-	AggregatorAttr       = 7
-	CommunitiesAttr      = 8
-	OriginatorIDAttr     = 9
-	ClusterListAttr      = 10
-	AS4PathAttr          = 17
-	AS4AggregatorAttr    = 18
-	LargeCommunitiesAttr = 32
+	OriginAttr              = types.OriginAttr
+	ASPathAttr              = types.ASPathAttr
+	NextHopAttr             = types.NextHopAttr
+	MEDAttr                 = types.MEDAttr
+	LocalPrefAttr           = types.LocalPrefAttr
+	AtomicAggrAttr          = types.AtomicAggrAttr
+	AggregatorAttr          = types.AggregatorAttr
+	CommunitiesAttr         = types.CommunitiesAttr
+	OriginatorIDAttr        = types.OriginatorIDAttr
+	ClusterListAttr         = types.ClusterListAttr
+	AS4PathAttr             = types.AS4PathAttr
+	AS4AggregatorAttr       = types.AS4AggregatorAttr
+	ExtendedCommunitiesAttr = types.ExtendedCommunitiesAttr
+	PMSITunnelAttr          = types.PMSITunnelAttr
+	AIGPAttr                = types.AIGPAttr
+	BGPLSAttr               = types.BGPLSAttr
+	LargeCommunitiesAttr    = types.LargeCommunitiesAttr
 
 	// ORIGIN values
 	IGP        = 0
@@ -105,8 +113,8 @@ const (
 	// Capabilities
 	CapabilitiesParamType        = 2
 	MultiProtocolCapabilityCode  = 1
-	MultiProtocolReachNLRICode   = 14
-	MultiProtocolUnreachNLRICode = 15
+	MultiProtocolReachNLRICode   = types.MPReachNLRIAttr
+	MultiProtocolUnreachNLRICode = types.MPUnreachNLRIAttr
 	AddPathCapabilityCode        = 69
 	ASN4CapabilityCode           = 65
 
@@ -115,7 +123,7 @@ const (
 	AddPathSend        = 2
 	AddPathSendReceive = 3
 
-	ASTransASN = 23456
+	ASTransASN = types.ASTransASN
 )
 
 var (
@@ -11,14 +11,18 @@ import (
 
 // MultiProtocolReachNLRI represents network layer reachability information for an IP address family (rfc4760)
 type MultiProtocolReachNLRI struct {
-	AFI     uint16
-	SAFI    uint8
-	NextHop *bnet.IP
-	NLRI    *NLRI
+	AFI              uint16
+	SAFI             uint8
+	NextHop          *bnet.IP
+	NextHopLinkLocal *bnet.IP // IPv6 link local next hop (RFC2545 3). Only set for 32 byte next hops.
+	NLRI             *NLRI
 }
 
 func (n *MultiProtocolReachNLRI) serialize(buf *bytes.Buffer, opt *EncodeOptions) uint16 {
 	nextHop := n.NextHop.Bytes()
+	if n.NextHopLinkLocal != nil {
+		nextHop = append(nextHop, n.NextHopLinkLocal.Bytes()...)
+	}
 
 	tempBuf := bytes.NewBuffer(nil)
 	tempBuf.Write(convert.Uint16Byte(n.AFI))
@@ -67,6 +71,12 @@ func deserializeMultiProtocolReachNLRI(b []byte, opt *DecodeOptions) (MultiProto
 	if nextHopLength == 32 {
 		// second next-hop is lladdr (see rfc2545 sec 3 par 2)
 		firstNextHopLength = 16
+
+		ll, err := bnet.IPFromBytes(variable[16:32])
+		if err != nil {
+			return MultiProtocolReachNLRI{}, fmt.Errorf("Failed to decode link local next hop IP: %w", err)
+		}
+		n.NextHopLinkLocal = ll.Dedup()
 	}
 	nh, err := bnet.IPFromBytes(variable[:firstNextHopLength])
 	if err != nil {
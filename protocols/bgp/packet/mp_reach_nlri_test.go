@@ -33,6 +33,26 @@ func TestSerializeMultiProtocolReachNLRI(t *testing.T) {
 				0x30, 0x26, 0x00, 0x00, 0x06, 0xff, 0x05, // Prefix
 			},
 		},
+		{
+			name: "IPv6 prefix with link local next hop",
+			nlri: MultiProtocolReachNLRI{
+				AFI:              AFIIPv6,
+				SAFI:             SAFIUnicast,
+				NextHop:          bnet.IPv6FromBlocks(0x2001, 0x678, 0x1e0, 0, 0, 0, 0, 0x2).Dedup(),
+				NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Dedup(),
+				NLRI: &NLRI{
+					Prefix: bnet.NewPfx(bnet.IPv6FromBlocks(0x2600, 0x6, 0xff05, 0, 0, 0, 0, 0), 48).Dedup(),
+				},
+			},
+			expected: []byte{
+				0x00, 0x02, // AFI
+				0x01,                                                                                                 // SAFI
+				0x20, 0x20, 0x01, 0x06, 0x78, 0x01, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, // NextHop
+				0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, // Link Local NextHop
+				0x00,                                     // RESERVED
+				0x30, 0x26, 0x00, 0x00, 0x06, 0xff, 0x05, // Prefix
+			},
+		},
 		{
 			name: "IPv6 prefix with ADD-PATH",
 			nlri: MultiProtocolReachNLRI{
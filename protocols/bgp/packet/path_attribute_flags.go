@@ -2,7 +2,9 @@ package packet
 
 import (
 	"bytes"
+	"fmt"
 
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
 	"github.com/bio-routing/bio-rd/util/decode"
 )
 
@@ -23,45 +25,110 @@ func decodePathAttrFlags(buf *bytes.Buffer, pa *PathAttribute) error {
 }
 
 func isOptional(x uint8) bool {
-	if x&128 == 128 {
+	if x&types.AttrFlagOptional == types.AttrFlagOptional {
 		return true
 	}
 	return false
 }
 
 func isTransitive(x uint8) bool {
-	if x&64 == 64 {
+	if x&types.AttrFlagTransitive == types.AttrFlagTransitive {
 		return true
 	}
 	return false
 }
 
 func isPartial(x uint8) bool {
-	if x&32 == 32 {
+	if x&types.AttrFlagPartial == types.AttrFlagPartial {
 		return true
 	}
 	return false
 }
 
 func isExtendedLength(x uint8) bool {
-	if x&16 == 16 {
+	if x&types.AttrFlagExtendedLength == types.AttrFlagExtendedLength {
 		return true
 	}
 	return false
 }
 
 func setOptional(x uint8) uint8 {
-	return x | 128
+	return x | types.AttrFlagOptional
 }
 
 func setTransitive(x uint8) uint8 {
-	return x | 64
+	return x | types.AttrFlagTransitive
 }
 
 func setPartial(x uint8) uint8 {
-	return x | 32
+	return x | types.AttrFlagPartial
 }
 
 func setExtendedLength(x uint8) uint8 {
-	return x | 16
+	return x | types.AttrFlagExtendedLength
+}
+
+// RawAttribute is a path attribute as received on the wire
+type RawAttribute struct {
+	Flags    uint8
+	TypeCode uint8
+	Value    []byte
+}
+
+type attrCategory struct {
+	optional   bool
+	transitive bool
+}
+
+var (
+	wellKnown             = attrCategory{optional: false, transitive: true}
+	optionalTransitive    = attrCategory{optional: true, transitive: true}
+	optionalNonTransitive = attrCategory{optional: true, transitive: false}
+
+	// mandatedAttrCategories holds the categories of the attributes known to BIO (RFC4271 5., RFC4456, RFC4760, ...)
+	mandatedAttrCategories = map[uint8]attrCategory{
+		OriginAttr:                   wellKnown,
+		ASPathAttr:                   wellKnown,
+		NextHopAttr:                  wellKnown,
+		MEDAttr:                      optionalNonTransitive,
+		LocalPrefAttr:                wellKnown,
+		AtomicAggrAttr:               wellKnown,
+		AggregatorAttr:               optionalTransitive,
+		CommunitiesAttr:              optionalTransitive,
+		OriginatorIDAttr:             optionalNonTransitive,
+		ClusterListAttr:              optionalNonTransitive,
+		MultiProtocolReachNLRICode:   optionalNonTransitive,
+		MultiProtocolUnreachNLRICode: optionalNonTransitive,
+		AS4PathAttr:                  optionalTransitive,
+		AS4AggregatorAttr:            optionalTransitive,
+		PMSITunnelAttr:               optionalTransitive,
+		AIGPAttr:                     optionalNonTransitive,
+		BGPLSAttr:                    optionalNonTransitive,
+		LargeCommunitiesAttr:         optionalTransitive,
+		ExtendedCommunitiesAttr:      optionalTransitive,
+	}
+)
+
+// ValidateReceivedAttributeFlags checks that the optional and transitive flags of the known attributes in raw
+// match the values mandated by the RFCs. The first violation is returned as BGPError (Attribute Flags Error).
+// Unknown attributes are not checked.
+func ValidateReceivedAttributeFlags(raw []RawAttribute) error {
+	for _, attr := range raw {
+		cat, ok := mandatedAttrCategories[attr.TypeCode]
+		if !ok {
+			continue
+		}
+
+		if isOptional(attr.Flags) == cat.optional && isTransitive(attr.Flags) == cat.transitive {
+			continue
+		}
+
+		return BGPError{
+			ErrorCode:    UpdateMessageError,
+			ErrorSubCode: AttrFlagsError,
+			ErrorStr:     fmt.Sprintf("Invalid flags 0x%02x for attribute type %d", attr.Flags, attr.TypeCode),
+		}
+	}
+
+	return nil
 }
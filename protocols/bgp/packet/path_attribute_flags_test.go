@@ -0,0 +1,58 @@
+package packet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReceivedAttributeFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []RawAttribute
+		expected error
+	}{
+		{
+			name: "Valid attributes",
+			input: []RawAttribute{
+				{Flags: 0x40, TypeCode: OriginAttr},
+				{Flags: 0x50, TypeCode: ASPathAttr},
+				{Flags: 0x80, TypeCode: MEDAttr},
+				{Flags: 0x40, TypeCode: LocalPrefAttr},
+				{Flags: 0xc0, TypeCode: CommunitiesAttr},
+				{Flags: 0xe0, TypeCode: LargeCommunitiesAttr},
+				{Flags: 0x00, TypeCode: 250},
+			},
+		},
+		{
+			name: "MED sent as transitive",
+			input: []RawAttribute{
+				{Flags: 0x40, TypeCode: OriginAttr},
+				{Flags: 0xc0, TypeCode: MEDAttr},
+			},
+			expected: BGPError{
+				ErrorCode:    UpdateMessageError,
+				ErrorSubCode: AttrFlagsError,
+				ErrorStr:     "Invalid flags 0xc0 for attribute type 4",
+			},
+		},
+		{
+			name: "LOCAL_PREF sent as optional",
+			input: []RawAttribute{
+				{Flags: 0xc0, TypeCode: LocalPrefAttr},
+				{Flags: 0xc0, TypeCode: MEDAttr},
+			},
+			expected: BGPError{
+				ErrorCode:    UpdateMessageError,
+				ErrorSubCode: AttrFlagsError,
+				ErrorStr:     "Invalid flags 0xc0 for attribute type 5",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ValidateReceivedAttributeFlags(test.input))
+		})
+	}
+}
@@ -158,6 +158,22 @@ func decodePathAttr(buf *bytes.Buffer, opt *DecodeOptions) (pa *PathAttribute, c
 		if err := pa.decodeLargeCommunities(buf); err != nil {
 			return nil, consumed, fmt.Errorf("Failed to decode large communities: %w", err)
 		}
+	case ExtendedCommunitiesAttr:
+		if err := pa.decodeExtendedCommunities(buf); err != nil {
+			return nil, consumed, fmt.Errorf("Failed to decode extended communities: %w", err)
+		}
+	case PMSITunnelAttr:
+		if err := pa.decodePMSITunnel(buf); err != nil {
+			return nil, consumed, fmt.Errorf("Failed to decode PMSI tunnel: %w", err)
+		}
+	case BGPLSAttr:
+		if err := pa.decodeBGPLS(buf); err != nil {
+			return nil, consumed, fmt.Errorf("Failed to decode BGP-LS attribute: %w", err)
+		}
+	case AIGPAttr:
+		if err := pa.decodeAIGP(buf); err != nil {
+			return nil, consumed, fmt.Errorf("Failed to decode AIGP attribute: %w", err)
+		}
 	default:
 		if err := pa.decodeUnknown(buf); err != nil {
 			return nil, consumed, fmt.Errorf("Failed to decode unknown attribute: %w", err)
@@ -217,6 +233,58 @@ func (pa *PathAttribute) decodeUnknown(buf *bytes.Buffer) error {
 	return nil
 }
 
+func (pa *PathAttribute) decodePMSITunnel(buf *bytes.Buffer) error {
+	b := make([]byte, pa.Length)
+
+	err := decode.Decode(buf, []interface{}{&b})
+	if err != nil {
+		return fmt.Errorf("unable to decode: %w", err)
+	}
+
+	t, err := types.DecodePMSITunnel(b)
+	if err != nil {
+		return err
+	}
+
+	pa.Value = t
+	return nil
+}
+
+func (pa *PathAttribute) decodeBGPLS(buf *bytes.Buffer) error {
+	b := make([]byte, pa.Length)
+
+	err := decode.Decode(buf, []interface{}{&b})
+	if err != nil {
+		return fmt.Errorf("unable to decode: %w", err)
+	}
+
+	pa.Value = &types.BGPLSAttribute{
+		Value: b,
+	}
+	return nil
+}
+
+// decodeAIGP sets pa.Value to the accumulated IGP metric. pa.Value stays nil if there is no AIGP TLV.
+func (pa *PathAttribute) decodeAIGP(buf *bytes.Buffer) error {
+	b := make([]byte, pa.Length)
+
+	err := decode.Decode(buf, []interface{}{&b})
+	if err != nil {
+		return fmt.Errorf("unable to decode: %w", err)
+	}
+
+	metric, found, err := types.DecodeAIGP(b)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		pa.Value = metric
+	}
+
+	return nil
+}
+
 func (pa *PathAttribute) decodeOrigin(buf *bytes.Buffer) error {
 	origin := uint8(0)
 
@@ -408,6 +476,29 @@ func (pa *PathAttribute) decodeLargeCommunities(buf *bytes.Buffer) error {
 	return nil
 }
 
+func (pa *PathAttribute) decodeExtendedCommunities(buf *bytes.Buffer) error {
+	if pa.Length%types.ExtendedCommunityLen != 0 {
+		return fmt.Errorf("unable to read extended community path attribute. Length %d is not divisible by 8", pa.Length)
+	}
+
+	b := make([]byte, types.ExtendedCommunityLen)
+	coms := make([]types.ExtendedCommunity, pa.Length/types.ExtendedCommunityLen)
+	for i := range coms {
+		err := decode.Decode(buf, []interface{}{&b})
+		if err != nil {
+			return fmt.Errorf("unable to decode: %w", err)
+		}
+
+		coms[i], err = types.ExtendedCommunityFromBytes(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	pa.Value = &coms
+	return nil
+}
+
 func (pa *PathAttribute) decodeAS4Aggregator(buf *bytes.Buffer) error {
 	return pa.decodeUint32(buf, "AS4Aggregator")
 }
@@ -528,6 +619,8 @@ func (pa *PathAttribute) Serialize(buf *bytes.Buffer, opt *EncodeOptions) uint16
 		pathAttrLen = uint16(pa.serializeCommunities(buf))
 	case LargeCommunitiesAttr:
 		pathAttrLen = uint16(pa.serializeLargeCommunities(buf))
+	case ExtendedCommunitiesAttr:
+		pathAttrLen = pa.serializeExtendedCommunities(buf)
 	case MultiProtocolReachNLRICode:
 		pathAttrLen = pa.serializeMultiProtocolReachNLRI(buf, opt)
 	case MultiProtocolUnreachNLRICode:
@@ -535,7 +628,13 @@ func (pa *PathAttribute) Serialize(buf *bytes.Buffer, opt *EncodeOptions) uint16
 	case OriginatorIDAttr:
 		pathAttrLen = uint16(pa.serializeOriginatorID(buf))
 	case ClusterListAttr:
-		pathAttrLen = uint16(pa.serializeClusterList(buf))
+		pathAttrLen = pa.serializeClusterList(buf)
+	case PMSITunnelAttr:
+		pathAttrLen = pa.serializePMSITunnel(buf)
+	case BGPLSAttr:
+		pathAttrLen = pa.serializeBGPLS(buf)
+	case AIGPAttr:
+		pathAttrLen = pa.serializeAIGP(buf)
 	default:
 		pathAttrLen = pa.serializeUnknownAttribute(buf)
 	}
@@ -593,6 +692,10 @@ func (pa *PathAttribute) serializeASPath(buf *bytes.Buffer, opt *EncodeOptions)
 
 	buf.Write(segmentsBuf.Bytes())
 
+	if length > 255 {
+		return length + 4
+	}
+
 	return length + 3
 }
 
@@ -730,6 +833,23 @@ func (pa *PathAttribute) serializeLargeCommunities(buf *bytes.Buffer) uint16 {
 	return length + 3
 }
 
+func (pa *PathAttribute) serializeExtendedCommunities(buf *bytes.Buffer) uint16 {
+	coms := pa.Value.(*[]types.ExtendedCommunity)
+	if len(*coms) == 0 {
+		return 0
+	}
+
+	b := make([]byte, 0, types.ExtendedCommunityLen*len(*coms))
+	for i := range *coms {
+		b = append(b, (*coms)[i].Bytes()...)
+	}
+
+	pa.Optional = true
+	pa.Transitive = true
+
+	return pa.serializeGeneric(b, buf)
+}
+
 func (pa *PathAttribute) serializeOriginatorID(buf *bytes.Buffer) uint8 {
 	attrFlags := uint8(0)
 	attrFlags = setOptional(attrFlags)
@@ -742,7 +862,7 @@ func (pa *PathAttribute) serializeOriginatorID(buf *bytes.Buffer) uint8 {
 	return 7
 }
 
-func (pa *PathAttribute) serializeClusterList(buf *bytes.Buffer) uint8 {
+func (pa *PathAttribute) serializeClusterList(buf *bytes.Buffer) uint16 {
 	if pa.Value == nil {
 		return 0
 	}
@@ -752,19 +872,15 @@ func (pa *PathAttribute) serializeClusterList(buf *bytes.Buffer) uint8 {
 		return 0
 	}
 
-	attrFlags := uint8(0)
-	attrFlags = setOptional(attrFlags)
-	buf.WriteByte(attrFlags)
-	buf.WriteByte(ClusterListAttr)
-
-	length := uint8(ClusterIDLen * len(*cids))
-	buf.WriteByte(length)
-
+	b := make([]byte, 0, ClusterIDLen*len(*cids))
 	for _, cid := range *cids {
-		buf.Write(convert.Uint32Byte(cid))
+		b = append(b, convert.Uint32Byte(cid)...)
 	}
 
-	return length + 3
+	pa.Optional = true
+	pa.Transitive = false
+
+	return pa.serializeGeneric(b, buf)
 }
 
 func (pa *PathAttribute) serializeUnknownAttribute(buf *bytes.Buffer) uint16 {
@@ -772,6 +888,12 @@ func (pa *PathAttribute) serializeUnknownAttribute(buf *bytes.Buffer) uint16 {
 	if pa.Optional {
 		attrFlags = setOptional(attrFlags)
 	}
+
+	b := pa.Value.([]byte)
+	if len(b) > math.MaxUint8 {
+		pa.ExtendedLength = true
+	}
+
 	if pa.ExtendedLength {
 		attrFlags = setExtendedLength(attrFlags)
 	}
@@ -780,19 +902,47 @@ func (pa *PathAttribute) serializeUnknownAttribute(buf *bytes.Buffer) uint16 {
 	buf.WriteByte(attrFlags)
 	buf.WriteByte(pa.TypeCode)
 
-	b := pa.Value.([]byte)
 	if pa.ExtendedLength {
 		l := len(b)
 		buf.WriteByte(uint8(l >> 8))
 		buf.WriteByte(uint8(l & 0x0000FFFF))
-	} else {
-		buf.WriteByte(uint8(len(b)))
+		buf.Write(b)
+		return uint16(len(b)) + 4
 	}
+
+	buf.WriteByte(uint8(len(b)))
 	buf.Write(b)
 
 	return uint16(len(b)) + 3
 }
 
+func (pa *PathAttribute) serializePMSITunnel(buf *bytes.Buffer) uint16 {
+	pa.Optional = true
+	pa.Transitive = true
+
+	return pa.serializeGeneric(pa.Value.(*types.PMSITunnel).Serialize(), buf)
+}
+
+func (pa *PathAttribute) serializeBGPLS(buf *bytes.Buffer) uint16 {
+	pa.Optional = true
+	pa.Transitive = false
+
+	return pa.serializeGeneric(pa.Value.(*types.BGPLSAttribute).Value, buf)
+}
+
+func (pa *PathAttribute) serializeAIGP(buf *bytes.Buffer) uint16 {
+	metric, ok := pa.Value.(uint64)
+	if !ok {
+		// No AIGP TLV was received, there is no metric to send
+		return 0
+	}
+
+	pa.Optional = true
+	pa.Transitive = false
+
+	return pa.serializeGeneric(types.SerializeAIGP(metric), buf)
+}
+
 func (pa *PathAttribute) serializeMultiProtocolReachNLRI(buf *bytes.Buffer, opt *EncodeOptions) uint16 {
 	v := pa.Value.(MultiProtocolReachNLRI)
 	pa.Optional = true
@@ -841,12 +991,14 @@ func (pa *PathAttribute) serializeGeneric(b []byte, buf *bytes.Buffer) uint16 {
 		l := len(b)
 		buf.WriteByte(uint8(l >> 8))
 		buf.WriteByte(uint8(l & 0x0000FFFF))
-	} else {
-		buf.WriteByte(uint8(len(b)))
+		buf.Write(b)
+		return uint16(len(b) + 4)
 	}
+
+	buf.WriteByte(uint8(len(b)))
 	buf.Write(b)
 
-	return uint16(len(b) + 2)
+	return uint16(len(b) + 3)
 }
 
 func fourBytesToUint32(address [4]byte) uint32 {
@@ -870,10 +1022,11 @@ func read4BytesAsUint32(buf *bytes.Buffer) (uint32, error) {
 func (pa *PathAttribute) AddOptionalPathAttributes(p *route.Path) *PathAttribute {
 	current := pa
 
-	if p.BGPPath.Communities != nil && len(*p.BGPPath.Communities) > 0 {
+	for _, chunk := range p.BGPPath.SplitCommunitiesForWire() {
+		coms := types.Communities(chunk)
 		communities := &PathAttribute{
 			TypeCode: CommunitiesAttr,
-			Value:    p.BGPPath.Communities,
+			Value:    &coms,
 		}
 		current.Next = communities
 		current = communities
@@ -888,6 +1041,42 @@ func (pa *PathAttribute) AddOptionalPathAttributes(p *route.Path) *PathAttribute
 		current = largeCommunities
 	}
 
+	if p.BGPPath.ExtendedCommunities != nil && len(*p.BGPPath.ExtendedCommunities) > 0 {
+		extCommunities := &PathAttribute{
+			TypeCode: ExtendedCommunitiesAttr,
+			Value:    p.BGPPath.ExtendedCommunities,
+		}
+		current.Next = extCommunities
+		current = extCommunities
+	}
+
+	if p.BGPPath.PMSITunnel != nil {
+		pmsiTunnel := &PathAttribute{
+			TypeCode: PMSITunnelAttr,
+			Value:    p.BGPPath.PMSITunnel,
+		}
+		current.Next = pmsiTunnel
+		current = pmsiTunnel
+	}
+
+	if p.BGPPath.BGPLSAttribute != nil {
+		bgpLS := &PathAttribute{
+			TypeCode: BGPLSAttr,
+			Value:    p.BGPPath.BGPLSAttribute,
+		}
+		current.Next = bgpLS
+		current = bgpLS
+	}
+
+	if p.BGPPath.BGPPathA.AIGPPresent {
+		aigp := &PathAttribute{
+			TypeCode: AIGPAttr,
+			Value:    p.BGPPath.BGPPathA.AIGP,
+		}
+		current.Next = aigp
+		current = aigp
+	}
+
 	return current
 }
 
@@ -913,7 +1102,7 @@ func PathAttributes(p *route.Path, iBGP bool, rrClient bool) (*PathAttribute, er
 	last.Next = nextHop
 	last = nextHop
 
-	if p.BGPPath.BGPPathA.MED != 0 {
+	if p.BGPPath.BGPPathA.MEDPresent() {
 		med := &PathAttribute{
 			TypeCode: MEDAttr,
 			Value:    p.BGPPath.BGPPathA.MED,
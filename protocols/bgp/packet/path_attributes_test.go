@@ -6,6 +6,7 @@ import (
 
 	bnet "github.com/bio-routing/bio-rd/net"
 	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/bio-routing/bio-rd/route"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -969,9 +970,10 @@ func TestDecodeMultiProtocolReachNLRI(t *testing.T) {
 			expected: &PathAttribute{
 				Length: 44,
 				Value: MultiProtocolReachNLRI{
-					AFI:     AFIIPv6,
-					SAFI:    SAFIUnicast,
-					NextHop: bnet.IPv6FromBlocks(0x2001, 0x678, 0x1e0, 0, 0, 0, 0, 0x2).Ptr(),
+					AFI:              AFIIPv6,
+					SAFI:             SAFIUnicast,
+					NextHop:          bnet.IPv6FromBlocks(0x2001, 0x678, 0x1e0, 0, 0, 0, 0, 0x2).Ptr(),
+					NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
 					NLRI: &NLRI{
 						Prefix: bnet.NewPfx(bnet.IPv6FromBlocks(0x2600, 0x6, 0xff05, 0, 0, 0, 0, 0), 48).Ptr(),
 					},
@@ -1720,6 +1722,18 @@ func TestSerializeLargeCommunities(t *testing.T) {
 }
 
 func TestSerializeCommunities(t *testing.T) {
+	// 65 communities exceed 255 bytes and require the extended length flag
+	manyCommunities := make(types.Communities, 65)
+	manyCommunitiesWire := []byte{
+		0xf0, // Attribute flags (optional, transitive, partial, extended length)
+		8,    // Type
+		1, 4, // Length (260)
+	}
+	for i := range manyCommunities {
+		manyCommunities[i] = uint32(i)
+		manyCommunitiesWire = append(manyCommunitiesWire, 0, 0, 0, uint8(i))
+	}
+
 	tests := []struct {
 		name        string
 		input       *PathAttribute
@@ -1742,6 +1756,15 @@ func TestSerializeCommunities(t *testing.T) {
 			},
 			expectedLen: 11,
 		},
+		{
+			name: "Extended length",
+			input: &PathAttribute{
+				TypeCode: CommunitiesAttr,
+				Value:    &manyCommunities,
+			},
+			expected:    manyCommunitiesWire,
+			expectedLen: 264,
+		},
 		{
 			name: "empty list of communities",
 			input: &PathAttribute{
@@ -1807,7 +1830,7 @@ func TestSerializeClusterList(t *testing.T) {
 		name        string
 		input       *PathAttribute
 		expected    []byte
-		expectedLen uint8
+		expectedLen uint16
 	}{
 		{
 			name: "Empty list of ClusterIDs",
@@ -1916,7 +1939,7 @@ func TestSerializeUnknownAttribute(t *testing.T) {
 				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 				0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // Payload
 			},
-			expectedLen: 259,
+			expectedLen: 260,
 		},
 	}
 
@@ -2425,3 +2448,289 @@ func TestPathAttributeCopy(t *testing.T) {
 	assert.Equal(t, *expected, *copy)
 	assert.False(t, attr == copy)
 }
+
+func TestDecodePMSITunnelAttr(t *testing.T) {
+	input := []byte{
+		0xc0,        // Attribute flags (optional, transitive)
+		22,          // Type
+		9,           // Length
+		0,           // Flags
+		6,           // Tunnel Type (Ingress Replication)
+		0, 0x27, 16, // VNI 10000
+		10, 0, 0, 1, // Tunnel Identifier
+	}
+
+	pa, _, err := decodePathAttr(bytes.NewBuffer(input), &DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &types.PMSITunnel{
+		TunnelType:       types.PMSITunnelTypeIngressReplication,
+		Label:            10000,
+		TunnelIdentifier: []byte{10, 0, 0, 1},
+	}
+	assert.Equal(t, expected, pa.Value)
+
+	buf := bytes.NewBuffer(nil)
+	pa.Serialize(buf, &EncodeOptions{})
+	assert.Equal(t, input, buf.Bytes())
+}
+
+func TestDecodeBGPLSAttr(t *testing.T) {
+	input := []byte{
+		0x80,       // Attribute flags (optional, non-transitive)
+		29,         // Type
+		15,         // Length
+		0x04, 0x02, // Node Name TLV
+		0, 4,
+		'r', 't', 'r', '1',
+		0x04, 0x47, // IGP Metric TLV
+		0, 3,
+		0, 0, 10,
+	}
+
+	pa, _, err := decodePathAttr(bytes.NewBuffer(input), &DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	attr := pa.Value.(*types.BGPLSAttribute)
+	assert.Equal(t, input[3:], attr.Value)
+
+	name, ok := attr.NodeName()
+	assert.True(t, ok)
+	assert.Equal(t, "rtr1", name)
+
+	metric, ok := attr.IGPMetric()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(10), metric)
+
+	buf := bytes.NewBuffer(nil)
+	pa.Serialize(buf, &EncodeOptions{})
+	assert.Equal(t, input, buf.Bytes())
+}
+
+func TestDecodeExtendedCommunitiesAttr(t *testing.T) {
+	input := []byte{
+		0xc0,                               // Attribute flags (optional, transitive)
+		16,                                 // Type
+		16,                                 // Length
+		0x00, 0x02, 0xfd, 0xe8, 0, 0, 0, 1, // Route target 65000:1
+		0x03, 0x0b, 0, 0, 0, 0, 0, 100, // Color 100
+	}
+
+	pa, _, err := decodePathAttr(bytes.NewBuffer(input), &DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	coms := pa.Value.(*[]types.ExtendedCommunity)
+	assert.Equal(t, []types.ExtendedCommunity{
+		{
+			Type:    types.ExtendedCommunityTypeTwoOctetAS,
+			SubType: types.ExtendedCommunitySubTypeRouteTarget,
+			Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+		},
+		{
+			Type:    0x03,
+			SubType: 0x0b,
+			Value:   [6]byte{0, 0, 0, 0, 0, 100},
+		},
+	}, *coms)
+
+	buf := bytes.NewBuffer(nil)
+	pa.Serialize(buf, &EncodeOptions{})
+	assert.Equal(t, input, buf.Bytes())
+
+	_, _, err = decodePathAttr(bytes.NewBuffer([]byte{0xc0, 16, 4, 0, 2, 0, 1}), &DecodeOptions{})
+	assert.Error(t, err)
+}
+
+func TestDecodeAIGPAttr(t *testing.T) {
+	input := []byte{
+		0x80,                             // Attribute flags (optional, non-transitive)
+		26,                               // Type
+		11,                               // Length
+		1, 0, 11, 0, 0, 0, 0, 0, 0, 1, 0, // AIGP TLV
+	}
+
+	pa, _, err := decodePathAttr(bytes.NewBuffer(input), &DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assert.Equal(t, uint64(256), pa.Value)
+
+	buf := bytes.NewBuffer(nil)
+	pa.Serialize(buf, &EncodeOptions{})
+	assert.Equal(t, input, buf.Bytes())
+}
+
+func TestSerializeAIGPAttrWithoutMetric(t *testing.T) {
+	input := []byte{
+		0x80,    // Attribute flags (optional, non-transitive)
+		26,      // Type
+		3,       // Length
+		2, 0, 3, // Unknown TLV
+	}
+
+	pa, _, err := decodePathAttr(bytes.NewBuffer(input), &DecodeOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assert.Nil(t, pa.Value)
+
+	buf := bytes.NewBuffer(nil)
+	pa.Serialize(buf, &EncodeOptions{})
+	assert.Empty(t, buf.Bytes())
+}
+
+func TestPathAttributesEBGPExportMED(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     route.EBGPExportOptions
+		expected bool
+	}{
+		{
+			name: "MED stripped",
+		},
+		{
+			name: "MED preserved to same AS",
+			opts: route.EBGPExportOptions{
+				PreserveMEDToSameAS: true,
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		p := &route.Path{
+			Type: route.BGPPathType,
+			BGPPath: &route.BGPPath{
+				BGPPathA: &route.BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					Source:  bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					HasMED:  true,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{65001},
+					},
+				},
+				ASPathLen: 1,
+			},
+		}
+
+		p.BGPPath.PrepareEBGPExport(65000, 65001, bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), test.opts)
+
+		attrs, err := PathAttributes(p, false, false)
+		assert.NoError(t, err, test.name)
+
+		found := false
+		for pa := attrs; pa != nil; pa = pa.Next {
+			if pa.TypeCode == MEDAttr {
+				found = true
+			}
+		}
+
+		assert.Equal(t, test.expected, found, test.name)
+	}
+}
+
+func TestPathAttributesLengthMatchesSerialization(t *testing.T) {
+	asns := make([]uint32, 40)
+	for i := range asns {
+		asns[i] = 4200000000 + uint32(i)
+	}
+
+	newPath := func(asPath types.ASPath) *route.Path {
+		return &route.Path{
+			Type: route.BGPPathType,
+			BGPPath: &route.BGPPath{
+				BGPPathA: &route.BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					Source:    bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+				},
+				ASPath:    &asPath,
+				ASPathLen: asPath.Length(),
+			},
+		}
+	}
+
+	serialize := func(p *route.Path) (total int, asPath uint16) {
+		attrs, err := PathAttributes(p, true, false)
+		assert.NoError(t, err)
+
+		buf := bytes.NewBuffer(nil)
+		for pa := attrs; pa != nil; pa = pa.Next {
+			n := pa.Serialize(buf, &EncodeOptions{
+				Use32BitASN: true,
+			})
+
+			if pa.TypeCode == ASPathAttr {
+				asPath = n
+			}
+		}
+
+		return buf.Len(), asPath
+	}
+
+	short := newPath(types.ASPath{})
+	long := newPath(types.ASPath{
+		{
+			Type: types.ASSequence,
+			ASNs: asns,
+		},
+		{
+			Type: types.ASSet,
+			ASNs: asns,
+		},
+	})
+
+	shortTotal, _ := serialize(short)
+	longTotal, longASPath := serialize(long)
+
+	assert.Greater(t, int(longASPath), 255, "AS path has to use extended length")
+	assert.Equal(t, longASPath, long.BGPPath.AttributeSizes()[route.AttributeSizeASPath])
+	assert.Equal(t, longTotal-shortTotal, int(long.BGPPath.Length())-int(short.BGPPath.Length()))
+}
+
+func TestSerializeGenericLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       []byte
+		expectedLen uint16
+	}{
+		{
+			name:        "Empty value",
+			value:       []byte{},
+			expectedLen: 3,
+		},
+		{
+			name:        "One byte length field",
+			value:       make([]byte, 255),
+			expectedLen: 258,
+		},
+		{
+			name:        "Extended length",
+			value:       make([]byte, 256),
+			expectedLen: 260,
+		},
+	}
+
+	for _, test := range tests {
+		pa := &PathAttribute{
+			TypeCode: MultiProtocolReachNLRICode,
+			Optional: true,
+		}
+
+		buf := bytes.NewBuffer(nil)
+		n := pa.serializeGeneric(test.value, buf)
+		assert.Equal(t, test.expectedLen, n, test.name)
+		assert.Equal(t, buf.Len(), int(n), test.name)
+	}
+}
@@ -182,6 +182,9 @@ func (f *fsmAddressFamily) newRoutePath() *route.Path {
 				Source: f.fsm.peer.addr,
 				EBGP:   f.fsm.peer.localASN != f.fsm.peer.peerASN,
 			},
+			AFI:        f.afi,
+			SAFI:       f.safi,
+			ReceivedAt: time.Now(),
 		},
 	}
 }
@@ -192,6 +195,7 @@ func (f *fsmAddressFamily) multiProtocolUpdate(path *route.Path, nlri packet.Mul
 	}
 
 	path.BGPPath.BGPPathA.NextHop = nlri.NextHop
+	path.BGPPath.BGPPathA.NextHopLinkLocal = nlri.NextHopLinkLocal
 
 	for n := nlri.NLRI; n != nil; n = n.Next {
 		f.adjRIBIn.AddPath(n.Prefix, path)
@@ -217,6 +221,7 @@ func (f *fsmAddressFamily) processAttributes(attrs *packet.PathAttribute, path *
 			path.BGPPath.BGPPathA.LocalPref = pa.Value.(uint32)
 		case packet.MEDAttr:
 			path.BGPPath.BGPPathA.MED = pa.Value.(uint32)
+			path.BGPPath.BGPPathA.HasMED = true
 		case packet.NextHopAttr:
 			path.BGPPath.BGPPathA.NextHop = pa.Value.(*bnet.IP)
 		case packet.ASPathAttr:
@@ -231,10 +236,21 @@ func (f *fsmAddressFamily) processAttributes(attrs *packet.PathAttribute, path *
 			path.BGPPath.Communities = pa.Value.(*types.Communities)
 		case packet.LargeCommunitiesAttr:
 			path.BGPPath.LargeCommunities = pa.Value.(*types.LargeCommunities)
+		case packet.ExtendedCommunitiesAttr:
+			path.BGPPath.ExtendedCommunities = pa.Value.(*[]types.ExtendedCommunity)
 		case packet.OriginatorIDAttr:
 			path.BGPPath.BGPPathA.OriginatorID = pa.Value.(uint32)
 		case packet.ClusterListAttr:
 			path.BGPPath.ClusterList = pa.Value.(*types.ClusterList)
+		case packet.PMSITunnelAttr:
+			path.BGPPath.PMSITunnel = pa.Value.(*types.PMSITunnel)
+		case packet.BGPLSAttr:
+			path.BGPPath.BGPLSAttribute = pa.Value.(*types.BGPLSAttribute)
+		case packet.AIGPAttr:
+			if metric, ok := pa.Value.(uint64); ok {
+				path.BGPPath.BGPPathA.AIGP = metric
+				path.BGPPath.BGPPathA.AIGPPresent = true
+			}
 		case packet.MultiProtocolReachNLRICode:
 		case packet.MultiProtocolUnreachNLRICode:
 		default:
@@ -158,7 +158,11 @@ func (u *UpdateSender) sender(aggrTime time.Duration) {
 }
 
 func (u *UpdateSender) getBudget(pathNLRIs *pathPfxs) int {
-	return packet.MaxLen - packet.HeaderLen - packet.MinUpdateLen - int(pathNLRIs.path.BGPPath.Length()) - u.updateOverhead()
+	p := pathNLRIs.path.BGPPath
+
+	// Length() sizes the next hop attribute by the family of the path, the overhead by the family of the session
+	nextHopAdjust := packet.IPv4Len + 3 - int(p.AttributeSizes()[route.AttributeSizeNextHop])
+	return packet.MaxLen - packet.HeaderLen - packet.MinUpdateLen - int(p.Length()) - u.updateOverhead() - nextHopAdjust
 }
 
 func (u *UpdateSender) updateOverhead() int {
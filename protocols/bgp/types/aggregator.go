@@ -1,5 +1,8 @@
 package types
 
+// ASTransASN is the 2 octet ASN standing in for a 4 octet ASN that can not be represented in 2 octets (RFC6793)
+const ASTransASN = 23456
+
 // Aggregator represents an AGGREGATOR attribute (type code 7) as in RFC4271
 type Aggregator struct {
 	Address uint32
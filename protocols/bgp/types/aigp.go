@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// AIGPTLVType is the type of the AIGP TLV within the AIGP attribute (RFC7311 3.)
+	AIGPTLVType = 1
+
+	// AIGPTLVLen is the length of the AIGP TLV including type and length fields
+	AIGPTLVLen = 11
+
+	aigpTLVHeaderLen = 3
+)
+
+// DecodeAIGP gets the accumulated IGP metric from the value of an AIGP attribute (type code 26).
+// TLVs of other types are skipped. Returns false if the attribute carries no AIGP TLV.
+func DecodeAIGP(b []byte) (uint64, bool, error) {
+	metric := uint64(0)
+	found := false
+
+	for len(b) > 0 {
+		if len(b) < aigpTLVHeaderLen {
+			return 0, false, fmt.Errorf("AIGP TLV header truncated: %d bytes left", len(b))
+		}
+
+		tlvType := b[0]
+		tlvLen := int(binary.BigEndian.Uint16(b[1:3]))
+		if tlvLen < aigpTLVHeaderLen || tlvLen > len(b) {
+			return 0, false, fmt.Errorf("invalid AIGP TLV length %d", tlvLen)
+		}
+
+		if tlvType == AIGPTLVType {
+			if found {
+				return 0, false, fmt.Errorf("AIGP TLV present more than once")
+			}
+
+			if tlvLen != AIGPTLVLen {
+				return 0, false, fmt.Errorf("invalid AIGP TLV length %d", tlvLen)
+			}
+
+			metric = binary.BigEndian.Uint64(b[aigpTLVHeaderLen:AIGPTLVLen])
+			found = true
+		}
+
+		b = b[tlvLen:]
+	}
+
+	return metric, found, nil
+}
+
+// SerializeAIGP returns the value of an AIGP attribute carrying metric
+func SerializeAIGP(metric uint64) []byte {
+	b := make([]byte, AIGPTLVLen)
+	b[0] = AIGPTLVType
+	binary.BigEndian.PutUint16(b[1:3], AIGPTLVLen)
+	binary.BigEndian.PutUint64(b[aigpTLVHeaderLen:], metric)
+
+	return b
+}
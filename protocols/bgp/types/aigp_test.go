@@ -0,0 +1,77 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeAIGP(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         []byte
+		wantFail      bool
+		expectedFound bool
+		expected      uint64
+	}{
+		{
+			name:          "AIGP TLV",
+			input:         []byte{1, 0, 11, 0, 0, 0, 0, 0, 0, 1, 0},
+			expectedFound: true,
+			expected:      256,
+		},
+		{
+			name: "Unknown TLV is skipped",
+			input: []byte{
+				200, 0, 4, 0xff,
+				1, 0, 11, 0, 0, 0, 0, 0, 0, 0, 10,
+			},
+			expectedFound: true,
+			expected:      10,
+		},
+		{
+			name:  "No AIGP TLV",
+			input: []byte{200, 0, 4, 0xff},
+		},
+		{
+			name:     "Invalid AIGP TLV length",
+			input:    []byte{1, 0, 10, 0, 0, 0, 0, 0, 0, 1},
+			wantFail: true,
+		},
+		{
+			name:     "Truncated TLV",
+			input:    []byte{1, 0, 11, 0, 0},
+			wantFail: true,
+		},
+		{
+			name: "AIGP TLV twice",
+			input: []byte{
+				1, 0, 11, 0, 0, 0, 0, 0, 0, 0, 10,
+				1, 0, 11, 0, 0, 0, 0, 0, 0, 0, 20,
+			},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		metric, found, err := DecodeAIGP(test.input)
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+		assert.Equal(t, test.expected, metric, test.name)
+	}
+}
+
+func TestSerializeAIGP(t *testing.T) {
+	b := SerializeAIGP(256)
+	assert.Equal(t, []byte{1, 0, 11, 0, 0, 0, 0, 0, 0, 1, 0}, b)
+
+	metric, found, err := DecodeAIGP(b)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(256), metric)
+}
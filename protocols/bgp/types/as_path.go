@@ -15,6 +15,12 @@ const (
 	// ASSequence is tha AS Path type used to indicate an AS Sequence (RFC4271)
 	ASSequence = 2
 
+	// ASConfedSequence is the AS Path type used to indicate an AS Confederation Sequence (RFC5065)
+	ASConfedSequence = 3
+
+	// ASConfedSet is the AS Path type used to indicate an AS Confederation Set (RFC5065)
+	ASConfedSet = 4
+
 	// MaxASNsSegment is the maximum number of ASNs in an AS segment
 	MaxASNsSegment = 255
 )
@@ -93,6 +99,11 @@ func (s ASPathSegment) GetLastASN() *uint32 {
 	return &ret
 }
 
+// IsConfed checks if the segment is an AS_CONFED_SEQUENCE or AS_CONFED_SET
+func (s ASPathSegment) IsConfed() bool {
+	return s.Type == ASConfedSequence || s.Type == ASConfedSet
+}
+
 // Compare checks if ASPathSegments are the same
 func (s ASPathSegment) Compare(t ASPathSegment) bool {
 	if s.Type != t.Type {
@@ -154,7 +165,7 @@ func ASPathFromProtoASPath(segments []*api.ASPathSegment) *ASPath {
 	return &asPath
 }
 
-// String converts an ASPath to it's human redable representation
+// String converts an ASPath to it's human redable representation. AS_SETs are rendered as "{a,b}".
 func (a *ASPath) String() string {
 	if a == nil {
 		return ""
@@ -172,6 +183,32 @@ func (a *ASPath) String() string {
 		}
 
 		if p.Type == ASSet {
+			setParts := make([]string, len(p.ASNs))
+			for i, asn := range p.ASNs {
+				setParts[i] = strconv.Itoa(int(asn))
+			}
+			parts = append(parts, "{"+strings.Join(setParts, ",")+"}")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ACLString renders the AS path as matched by AS path access lists. Contrary to String, AS_SETs are
+// rendered as "(a b)" to keep existing regular expressions working.
+func (a *ASPath) ACLString() string {
+	if a == nil {
+		return ""
+	}
+
+	parts := make([]string, 0)
+	for _, p := range *a {
+		switch p.Type {
+		case ASSequence:
+			for _, asn := range p.ASNs {
+				parts = append(parts, strconv.Itoa(int(asn)))
+			}
+		case ASSet:
 			setParts := make([]string, len(p.ASNs))
 			for i, asn := range p.ASNs {
 				setParts[i] = strconv.Itoa(int(asn))
@@ -183,9 +220,40 @@ func (a *ASPath) String() string {
 	return strings.Join(parts, " ")
 }
 
-// Length returns the AS path length as used by path selection
+// HashString serializes the AS path for hashing. Other than String it includes all segments with their
+// type (including confederation segments) and does not change with the display format.
+func (a *ASPath) HashString() string {
+	if a == nil {
+		return ""
+	}
+
+	b := &strings.Builder{}
+	for i, p := range *a {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+
+		b.WriteString(strconv.Itoa(int(p.Type)))
+		b.WriteByte(':')
+		for j, asn := range p.ASNs {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+
+			b.WriteString(strconv.FormatUint(uint64(asn), 10))
+		}
+	}
+
+	return b.String()
+}
+
+// Length returns the AS path length as used by path selection. Confederation segments are not counted (RFC5065 5.3).
 func (pa ASPath) Length() (ret uint16) {
 	for _, p := range pa {
+		if p.IsConfed() {
+			continue
+		}
+
 		if p.Type == ASSet {
 			ret++
 			continue
@@ -321,7 +321,7 @@ func TestASPathString(t *testing.T) {
 					ASNs: []uint32{100, 2},
 				},
 			},
-			expected: "3 4 5 62 (100 2)",
+			expected: "3 4 5 62 {100,2}",
 		}, {
 			name: "test one Set",
 			asPath: &ASPath{
@@ -330,7 +330,7 @@ func TestASPathString(t *testing.T) {
 					ASNs: []uint32{1, 2},
 				},
 			},
-			expected: "(1 2)",
+			expected: "{1,2}",
 		}, {
 			name:     "test empty",
 			asPath:   &ASPath{},
@@ -351,6 +351,94 @@ func TestASPathStringNil(t *testing.T) {
 	assert.Empty(t, actual)
 }
 
+func TestASPathACLString(t *testing.T) {
+	a := &ASPath{
+		{
+			Type: ASConfedSequence,
+			ASNs: []uint32{65000},
+		},
+		{
+			Type: ASSequence,
+			ASNs: []uint32{3320, 201701},
+		},
+		{
+			Type: ASSet,
+			ASNs: []uint32{1, 2},
+		},
+	}
+
+	assert.Equal(t, "3320 201701 (1 2)", a.ACLString())
+}
+
+func TestASPathHashString(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *ASPath
+		b    *ASPath
+	}{
+		{
+			name: "Confed segment only",
+			a: &ASPath{
+				{
+					Type: ASSequence,
+					ASNs: []uint32{3320},
+				},
+			},
+			b: &ASPath{
+				{
+					Type: ASConfedSequence,
+					ASNs: []uint32{65000},
+				},
+				{
+					Type: ASSequence,
+					ASNs: []uint32{3320},
+				},
+			},
+		},
+		{
+			name: "Segment type",
+			a: &ASPath{
+				{
+					Type: ASSequence,
+					ASNs: []uint32{1, 2},
+				},
+			},
+			b: &ASPath{
+				{
+					Type: ASSet,
+					ASNs: []uint32{1, 2},
+				},
+			},
+		},
+		{
+			name: "Segment boundaries",
+			a: &ASPath{
+				{
+					Type: ASSequence,
+					ASNs: []uint32{1, 2},
+				},
+			},
+			b: &ASPath{
+				{
+					Type: ASSequence,
+					ASNs: []uint32{1},
+				},
+				{
+					Type: ASSequence,
+					ASNs: []uint32{2},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.NotEqual(t, test.a.HashString(), test.b.HashString(), test.name)
+	}
+
+	var n *ASPath
+	assert.Empty(t, n.HashString())
+}
+
 func TestASPathLength(t *testing.T) {
 	a := &ASPath{
 		ASPathSegment{
@@ -366,6 +454,14 @@ func TestASPathLength(t *testing.T) {
 			Type: ASSet,
 			ASNs: []uint32{1, 2},
 		},
+		ASPathSegment{
+			Type: ASConfedSequence,
+			ASNs: []uint32{65010, 65011},
+		},
+		ASPathSegment{
+			Type: ASConfedSet,
+			ASNs: []uint32{65012},
+		},
 	}
 
 	actual := a.Length()
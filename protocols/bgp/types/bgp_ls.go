@@ -0,0 +1,149 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/bio-routing/tflow2/convert"
+)
+
+// BGP-LS attribute TLV types (RFC7752 3.3)
+const (
+	BGPLSTLVNodeName          = 1026
+	BGPLSTLVIPv4RouterIDLocal = 1028
+	BGPLSTLVIPv6RouterIDLocal = 1029
+	BGPLSTLVMaxLinkBandwidth  = 1089
+	BGPLSTLVTEDefaultMetric   = 1092
+	BGPLSTLVIGPMetric         = 1095
+	BGPLSTLVPrefixMetric      = 1155
+
+	bgpLSTLVHeaderLen = 4
+)
+
+// BGPLSAttribute represents a BGP-LS attribute (type code 29) as in RFC7752. The value is kept as received
+// to allow loss free re-serialization, TLVs are decoded on access.
+type BGPLSAttribute struct {
+	Value []byte
+}
+
+// BGPLSTLV is a TLV of a BGP-LS attribute
+type BGPLSTLV struct {
+	Type  uint16
+	Value []byte
+}
+
+// TLVs decodes the TLVs of the attribute
+func (a *BGPLSAttribute) TLVs() ([]BGPLSTLV, error) {
+	ret := make([]BGPLSTLV, 0)
+	for i := 0; i < len(a.Value); {
+		if len(a.Value)-i < bgpLSTLVHeaderLen {
+			return nil, fmt.Errorf("TLV header truncated at offset %d", i)
+		}
+
+		typ := convert.Uint16b(a.Value[i : i+2])
+		length := int(convert.Uint16b(a.Value[i+2 : i+4]))
+		i += bgpLSTLVHeaderLen
+
+		if len(a.Value)-i < length {
+			return nil, fmt.Errorf("TLV %d truncated: expected %d bytes, got %d", typ, length, len(a.Value)-i)
+		}
+
+		ret = append(ret, BGPLSTLV{
+			Type:  typ,
+			Value: a.Value[i : i+length],
+		})
+		i += length
+	}
+
+	return ret, nil
+}
+
+// TLV gets the value of the first TLV of type typ
+func (a *BGPLSAttribute) TLV(typ uint16) ([]byte, bool) {
+	tlvs, err := a.TLVs()
+	if err != nil {
+		return nil, false
+	}
+
+	for _, tlv := range tlvs {
+		if tlv.Type == typ {
+			return tlv.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// NodeName gets the node name TLV
+func (a *BGPLSAttribute) NodeName() (string, bool) {
+	v, ok := a.TLV(BGPLSTLVNodeName)
+	if !ok {
+		return "", false
+	}
+
+	return string(v), true
+}
+
+// IGPMetric gets the IGP metric TLV of a link (1 to 3 octets)
+func (a *BGPLSAttribute) IGPMetric() (uint32, bool) {
+	v, ok := a.TLV(BGPLSTLVIGPMetric)
+	if !ok || len(v) == 0 || len(v) > 3 {
+		return 0, false
+	}
+
+	metric := uint32(0)
+	for _, x := range v {
+		metric = metric<<8 | uint32(x)
+	}
+
+	return metric, true
+}
+
+// Compare checks if a and b are equal
+func (a *BGPLSAttribute) Compare(b *BGPLSAttribute) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if len(a.Value) != len(b.Value) {
+		return false
+	}
+
+	for i := range a.Value {
+		if a.Value[i] != b.Value[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy returns a deep copy of a
+func (a *BGPLSAttribute) Copy() *BGPLSAttribute {
+	if a == nil {
+		return nil
+	}
+
+	v := make([]byte, len(a.Value))
+	copy(v, a.Value)
+	return &BGPLSAttribute{
+		Value: v,
+	}
+}
+
+// WireLength returns the number of bytes the attribute needs on the wire
+func (a *BGPLSAttribute) WireLength() uint16 {
+	length := uint16(len(a.Value))
+	if length > 255 {
+		length++ // Extended length
+	}
+
+	return length + 3
+}
+
+func (a *BGPLSAttribute) String() string {
+	if a == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", a.Value)
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBGPLSAttributeTLVs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected []BGPLSTLV
+		wantFail bool
+	}{
+		{
+			name:     "Empty",
+			input:    []byte{},
+			expected: []BGPLSTLV{},
+		},
+		{
+			name: "Node name and IGP metric",
+			input: []byte{
+				0x04, 0x02, 0, 2, 'r', '1',
+				0x04, 0x47, 0, 1, 20,
+			},
+			expected: []BGPLSTLV{
+				{
+					Type:  BGPLSTLVNodeName,
+					Value: []byte{'r', '1'},
+				},
+				{
+					Type:  BGPLSTLVIGPMetric,
+					Value: []byte{20},
+				},
+			},
+		},
+		{
+			name:     "Truncated header",
+			input:    []byte{0x04, 0x02, 0},
+			wantFail: true,
+		},
+		{
+			name:     "Truncated value",
+			input:    []byte{0x04, 0x02, 0, 5, 'r'},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		a := &BGPLSAttribute{Value: test.input}
+		res, err := a.TLVs()
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, res, test.name)
+	}
+}
+
+func TestBGPLSAttributeIGPMetric(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected uint32
+		ok       bool
+	}{
+		{
+			name:     "3 octet metric",
+			input:    []byte{0x04, 0x47, 0, 3, 0x01, 0x00, 0x02},
+			expected: 65538,
+			ok:       true,
+		},
+		{
+			name:  "Too long",
+			input: []byte{0x04, 0x47, 0, 4, 0, 0, 0, 1},
+		},
+		{
+			name:  "Missing",
+			input: []byte{0x04, 0x02, 0, 1, 'r'},
+		},
+	}
+
+	for _, test := range tests {
+		a := &BGPLSAttribute{Value: test.input}
+		res, ok := a.IGPMetric()
+		assert.Equal(t, test.ok, ok, test.name)
+		assert.Equal(t, test.expected, res, test.name)
+	}
+}
+
+func TestBGPLSAttributeCopy(t *testing.T) {
+	a := &BGPLSAttribute{Value: []byte{1, 2, 3}}
+	cp := a.Copy()
+	assert.True(t, a.Compare(cp))
+
+	cp.Value[0] = 100
+	assert.False(t, a.Compare(cp))
+
+	var n *BGPLSAttribute
+	assert.Nil(t, n.Copy())
+	assert.True(t, n.Compare(nil))
+	assert.False(t, n.Compare(a))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -11,6 +12,13 @@ const (
 	WellKnownCommunityNoExport = 0xFFFFFF01
 	// WellKnownCommunityNoAdvertise is the well known no advertise BGP community (RFC1997)
 	WellKnownCommunityNoAdvertise = 0xFFFFFF02
+	// WellKnownCommunityGracefulShutdown is the well known graceful shutdown BGP community (RFC8326)
+	WellKnownCommunityGracefulShutdown = 0xFFFF0000
+	// WellKnownCommunityBlackhole is the well known blackhole BGP community (RFC7999)
+	WellKnownCommunityBlackhole = 0xFFFF029A
+
+	// MaxCommunitiesPerAttribute is the maximum number of communities fitting into one COMMUNITIES attribute
+	MaxCommunitiesPerAttribute = math.MaxUint16 / 4
 )
 
 // CommunityStringForUint32 transforms a community into a human readable representation
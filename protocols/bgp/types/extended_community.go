@@ -0,0 +1,110 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bio-routing/bio-rd/route/api"
+	"github.com/bio-routing/tflow2/convert"
+)
+
+const (
+	// ExtendedCommunityLen is the length of an extended community on the wire
+	ExtendedCommunityLen = 8
+
+	// Extended community types (RFC4360 3., RFC5668 2.)
+	ExtendedCommunityTypeTwoOctetAS  = 0x00
+	ExtendedCommunityTypeIPv4Address = 0x01
+	ExtendedCommunityTypeFourOctetAS = 0x02
+
+	// Extended community sub types (RFC4360 4., 5.)
+	ExtendedCommunitySubTypeRouteTarget = 0x02
+	ExtendedCommunitySubTypeRouteOrigin = 0x03
+)
+
+// ExtendedCommunity represents an extended community (RFC4360)
+type ExtendedCommunity struct {
+	Type    uint8
+	SubType uint8
+	Value   [6]byte
+}
+
+// ExtendedCommunityFromBytes decodes an extended community from its wire representation
+func ExtendedCommunityFromBytes(b []byte) (ExtendedCommunity, error) {
+	if len(b) != ExtendedCommunityLen {
+		return ExtendedCommunity{}, fmt.Errorf("invalid extended community length: %d", len(b))
+	}
+
+	c := ExtendedCommunity{
+		Type:    b[0],
+		SubType: b[1],
+	}
+
+	copy(c.Value[:], b[2:])
+	return c, nil
+}
+
+// Bytes returns the wire representation of the extended community
+func (c *ExtendedCommunity) Bytes() []byte {
+	return append([]byte{c.Type, c.SubType}, c.Value[:]...)
+}
+
+// ToProto converts ExtendedCommunity to proto ExtendedCommunity
+func (c *ExtendedCommunity) ToProto() *api.ExtendedCommunity {
+	return &api.ExtendedCommunity{
+		Type:    uint32(c.Type),
+		SubType: uint32(c.SubType),
+		Value:   append([]byte{}, c.Value[:]...),
+	}
+}
+
+// ExtendedCommunityFromProtoExtendedCommunity converts a proto ExtendedCommunity to ExtendedCommunity
+func ExtendedCommunityFromProtoExtendedCommunity(x *api.ExtendedCommunity) ExtendedCommunity {
+	c := ExtendedCommunity{
+		Type:    uint8(x.Type),
+		SubType: uint8(x.SubType),
+	}
+
+	copy(c.Value[:], x.Value)
+	return c
+}
+
+// String transitions an extended community to it's human readable representation.
+// Route targets and route origins are rendered as (rt|ro, global administrator, local administrator),
+// all other extended communities in hex.
+func (c *ExtendedCommunity) String() string {
+	prefix := ""
+	switch c.SubType {
+	case ExtendedCommunitySubTypeRouteTarget:
+		prefix = "rt"
+	case ExtendedCommunitySubTypeRouteOrigin:
+		prefix = "ro"
+	}
+
+	if prefix != "" {
+		switch c.Type {
+		case ExtendedCommunityTypeTwoOctetAS:
+			return fmt.Sprintf("(%s,%d,%d)", prefix, convert.Uint16b(c.Value[0:2]), convert.Uint32b(c.Value[2:6]))
+		case ExtendedCommunityTypeIPv4Address:
+			return fmt.Sprintf("(%s,%d.%d.%d.%d,%d)", prefix, c.Value[0], c.Value[1], c.Value[2], c.Value[3], convert.Uint16b(c.Value[4:6]))
+		case ExtendedCommunityTypeFourOctetAS:
+			return fmt.Sprintf("(%s,%d,%d)", prefix, convert.Uint32b(c.Value[0:4]), convert.Uint16b(c.Value[4:6]))
+		}
+	}
+
+	return fmt.Sprintf("0x%02x%02x%x", c.Type, c.SubType, c.Value[:])
+}
+
+// ExtendedCommunitiesString formats a list of extended communities separated by spaces
+func ExtendedCommunitiesString(coms []ExtendedCommunity) string {
+	str := &strings.Builder{}
+	for i := range coms {
+		if i > 0 {
+			str.WriteByte(' ')
+		}
+
+		str.WriteString(coms[i].String())
+	}
+
+	return str.String()
+}
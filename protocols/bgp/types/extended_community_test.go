@@ -0,0 +1,92 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendedCommunityFromBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantFail bool
+		expected ExtendedCommunity
+	}{
+		{
+			name:  "Route target",
+			input: []byte{0x00, 0x02, 0xfd, 0xe8, 0, 0, 0, 1},
+			expected: ExtendedCommunity{
+				Type:    ExtendedCommunityTypeTwoOctetAS,
+				SubType: ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+		},
+		{
+			name:     "Too short",
+			input:    []byte{0x00, 0x02, 0xfd, 0xe8},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		c, err := ExtendedCommunityFromBytes(test.input)
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, c, test.name)
+		assert.Equal(t, test.input, c.Bytes(), test.name)
+	}
+}
+
+func TestExtendedCommunityString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    ExtendedCommunity
+		expected string
+	}{
+		{
+			name: "Two octet AS route target",
+			input: ExtendedCommunity{
+				Type:    ExtendedCommunityTypeTwoOctetAS,
+				SubType: ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+			expected: "(rt,65000,1)",
+		},
+		{
+			name: "IPv4 route origin",
+			input: ExtendedCommunity{
+				Type:    ExtendedCommunityTypeIPv4Address,
+				SubType: ExtendedCommunitySubTypeRouteOrigin,
+				Value:   [6]byte{10, 0, 0, 1, 0, 100},
+			},
+			expected: "(ro,10.0.0.1,100)",
+		},
+		{
+			name: "Four octet AS route target",
+			input: ExtendedCommunity{
+				Type:    ExtendedCommunityTypeFourOctetAS,
+				SubType: ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0, 3, 0x0d, 0x40, 0, 7},
+			},
+			expected: "(rt,200000,7)",
+		},
+		{
+			name: "Color",
+			input: ExtendedCommunity{
+				Type:    0x03,
+				SubType: 0x0b,
+				Value:   [6]byte{0, 0, 0, 0, 0, 100},
+			},
+			expected: "0x030b000000000064",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.input.String(), test.name)
+	}
+}
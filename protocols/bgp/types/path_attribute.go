@@ -0,0 +1,32 @@
+package types
+
+// Path attribute flags (RFC4271 4.3)
+const (
+	AttrFlagOptional       = 128
+	AttrFlagTransitive     = 64
+	AttrFlagPartial        = 32
+	AttrFlagExtendedLength = 16
+)
+
+// Path attribute type codes
+const (
+	OriginAttr              = 1
+	ASPathAttr              = 2
+	NextHopAttr             = 3
+	MEDAttr                 = 4
+	LocalPrefAttr           = 5
+	AtomicAggrAttr          = 6
+	AggregatorAttr          = 7
+	CommunitiesAttr         = 8
+	OriginatorIDAttr        = 9
+	ClusterListAttr         = 10
+	MPReachNLRIAttr         = 14
+	MPUnreachNLRIAttr       = 15
+	ExtendedCommunitiesAttr = 16
+	AS4PathAttr             = 17
+	AS4AggregatorAttr       = 18
+	PMSITunnelAttr          = 22
+	AIGPAttr                = 26
+	BGPLSAttr               = 29
+	LargeCommunitiesAttr    = 32
+)
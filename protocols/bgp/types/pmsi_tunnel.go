@@ -0,0 +1,155 @@
+package types
+
+import (
+	"fmt"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/route/api"
+)
+
+const (
+	// PMSITunnelHeaderLen is the length of the PMSI Tunnel attribute without the tunnel identifier
+	PMSITunnelHeaderLen = 5
+
+	// PMSITunnelTypeIngressReplication is the ingress replication tunnel type (RFC6514)
+	PMSITunnelTypeIngressReplication = 6
+)
+
+// PMSITunnel represents a PMSI_TUNNEL attribute (type code 22) as in RFC6514
+type PMSITunnel struct {
+	Flags      uint8
+	TunnelType uint8
+	// Label holds the raw 24 bit MPLS label field. For EVPN VXLAN this carries the VNI (RFC8365).
+	Label uint32
+	// TunnelIdentifier is kept opaque as its format depends on the tunnel type
+	TunnelIdentifier []byte
+}
+
+// DecodePMSITunnel decodes the value of a PMSI_TUNNEL attribute
+func DecodePMSITunnel(b []byte) (*PMSITunnel, error) {
+	if len(b) < PMSITunnelHeaderLen {
+		return nil, fmt.Errorf("PMSI tunnel attribute too short: %d bytes", len(b))
+	}
+
+	t := &PMSITunnel{
+		Flags:            b[0],
+		TunnelType:       b[1],
+		Label:            uint32(b[2])<<16 | uint32(b[3])<<8 | uint32(b[4]),
+		TunnelIdentifier: make([]byte, len(b)-PMSITunnelHeaderLen),
+	}
+
+	copy(t.TunnelIdentifier, b[PMSITunnelHeaderLen:])
+	return t, nil
+}
+
+// Serialize returns the wire representation of the attributes value
+func (t *PMSITunnel) Serialize() []byte {
+	b := make([]byte, PMSITunnelHeaderLen, PMSITunnelHeaderLen+len(t.TunnelIdentifier))
+	b[0] = t.Flags
+	b[1] = t.TunnelType
+	b[2] = uint8(t.Label >> 16)
+	b[3] = uint8(t.Label >> 8)
+	b[4] = uint8(t.Label)
+
+	return append(b, t.TunnelIdentifier...)
+}
+
+// Length returns the length of the attributes value
+func (t *PMSITunnel) Length() uint16 {
+	return uint16(PMSITunnelHeaderLen + len(t.TunnelIdentifier))
+}
+
+// IngressReplicationEndpoint returns the endpoint address of an ingress replication tunnel
+func (t *PMSITunnel) IngressReplicationEndpoint() (bnet.IP, bool) {
+	if t.TunnelType != PMSITunnelTypeIngressReplication {
+		return bnet.IP{}, false
+	}
+
+	addr, err := bnet.IPFromBytes(t.TunnelIdentifier)
+	if err != nil {
+		return bnet.IP{}, false
+	}
+
+	return addr, true
+}
+
+// Compare checks if t and s are equal
+func (t *PMSITunnel) Compare(s *PMSITunnel) bool {
+	if t == nil || s == nil {
+		return t == s
+	}
+
+	if t.Flags != s.Flags || t.TunnelType != s.TunnelType || t.Label != s.Label {
+		return false
+	}
+
+	if len(t.TunnelIdentifier) != len(s.TunnelIdentifier) {
+		return false
+	}
+
+	for i := range t.TunnelIdentifier {
+		if t.TunnelIdentifier[i] != s.TunnelIdentifier[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Copy returns a deep copy of t
+func (t *PMSITunnel) Copy() *PMSITunnel {
+	if t == nil {
+		return nil
+	}
+
+	cp := *t
+	cp.TunnelIdentifier = make([]byte, len(t.TunnelIdentifier))
+	copy(cp.TunnelIdentifier, t.TunnelIdentifier)
+	return &cp
+}
+
+func (t *PMSITunnel) String() string {
+	if t == nil {
+		return ""
+	}
+
+	if addr, ok := t.IngressReplicationEndpoint(); ok {
+		return fmt.Sprintf("flags %d type %d label %d endpoint %s", t.Flags, t.TunnelType, t.Label, addr.String())
+	}
+
+	return fmt.Sprintf("flags %d type %d label %d id %x", t.Flags, t.TunnelType, t.Label, t.TunnelIdentifier)
+}
+
+// ToProto converts PMSITunnel to proto PMSITunnel
+func (t *PMSITunnel) ToProto() *api.PMSITunnel {
+	if t == nil {
+		return nil
+	}
+
+	a := &api.PMSITunnel{
+		Flags:            uint32(t.Flags),
+		TunnelType:       uint32(t.TunnelType),
+		Label:            t.Label,
+		TunnelIdentifier: make([]byte, len(t.TunnelIdentifier)),
+	}
+
+	copy(a.TunnelIdentifier, t.TunnelIdentifier)
+	return a
+}
+
+// PMSITunnelFromProtoPMSITunnel converts a proto PMSITunnel to PMSITunnel
+func PMSITunnelFromProtoPMSITunnel(x *api.PMSITunnel) *PMSITunnel {
+	if x == nil {
+		return nil
+	}
+
+	t := &PMSITunnel{
+		Flags:            uint8(x.Flags),
+		TunnelType:       uint8(x.TunnelType),
+		Label:            x.Label,
+		TunnelIdentifier: make([]byte, len(x.TunnelIdentifier)),
+	}
+
+	copy(t.TunnelIdentifier, x.TunnelIdentifier)
+	return t
+}
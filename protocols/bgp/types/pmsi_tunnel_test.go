@@ -0,0 +1,109 @@
+package types
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePMSITunnel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		wantFail bool
+		expected *PMSITunnel
+	}{
+		{
+			name: "Ingress replication",
+			input: []byte{
+				0,           // Flags
+				6,           // Tunnel Type (Ingress Replication)
+				0, 0x27, 16, // Label/VNI 10000
+				10, 0, 0, 1, // Tunnel Identifier
+			},
+			expected: &PMSITunnel{
+				Flags:            0,
+				TunnelType:       PMSITunnelTypeIngressReplication,
+				Label:            10000,
+				TunnelIdentifier: []byte{10, 0, 0, 1},
+			},
+		},
+		{
+			name: "Unknown tunnel type",
+			input: []byte{
+				1,       // Flags
+				200,     // Tunnel Type
+				0, 0, 1, // Label
+				1, 2, 3, // Tunnel Identifier
+			},
+			expected: &PMSITunnel{
+				Flags:            1,
+				TunnelType:       200,
+				Label:            1,
+				TunnelIdentifier: []byte{1, 2, 3},
+			},
+		},
+		{
+			name:     "Too short",
+			input:    []byte{0, 6, 0, 0},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := DecodePMSITunnel(test.input)
+			if test.wantFail {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+			assert.Equal(t, test.input, res.Serialize())
+			assert.Equal(t, res, PMSITunnelFromProtoPMSITunnel(res.ToProto()))
+		})
+	}
+}
+
+func TestPMSITunnelIngressReplicationEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *PMSITunnel
+		expected bnet.IP
+		ok       bool
+	}{
+		{
+			name: "IPv4 endpoint",
+			input: &PMSITunnel{
+				TunnelType:       PMSITunnelTypeIngressReplication,
+				TunnelIdentifier: []byte{10, 0, 0, 1},
+			},
+			expected: bnet.IPv4FromOctets(10, 0, 0, 1),
+			ok:       true,
+		},
+		{
+			name: "Other tunnel type",
+			input: &PMSITunnel{
+				TunnelType:       3,
+				TunnelIdentifier: []byte{10, 0, 0, 1},
+			},
+		},
+		{
+			name: "Malformed identifier",
+			input: &PMSITunnel{
+				TunnelType:       PMSITunnelTypeIngressReplication,
+				TunnelIdentifier: []byte{10, 0, 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addr, ok := test.input.IngressReplicationEndpoint()
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, addr)
+		})
+	}
+}
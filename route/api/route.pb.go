@@ -237,20 +237,27 @@ type BGPPath struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PathIdentifier    uint32                  `protobuf:"varint,1,opt,name=path_identifier,json=pathIdentifier,proto3" json:"path_identifier,omitempty"`
-	NextHop           *api.IP                 `protobuf:"bytes,2,opt,name=next_hop,json=nextHop,proto3" json:"next_hop,omitempty"`
-	LocalPref         uint32                  `protobuf:"varint,3,opt,name=local_pref,json=localPref,proto3" json:"local_pref,omitempty"`
-	AsPath            []*ASPathSegment        `protobuf:"bytes,4,rep,name=as_path,json=asPath,proto3" json:"as_path,omitempty"`
-	Origin            uint32                  `protobuf:"varint,5,opt,name=origin,proto3" json:"origin,omitempty"`
-	Med               uint32                  `protobuf:"varint,6,opt,name=med,proto3" json:"med,omitempty"`
-	Ebgp              bool                    `protobuf:"varint,7,opt,name=ebgp,proto3" json:"ebgp,omitempty"`
-	BgpIdentifier     uint32                  `protobuf:"varint,8,opt,name=bgp_identifier,json=bgpIdentifier,proto3" json:"bgp_identifier,omitempty"`
-	Source            *api.IP                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`
-	Communities       []uint32                `protobuf:"varint,10,rep,packed,name=communities,proto3" json:"communities,omitempty"`
-	LargeCommunities  []*LargeCommunity       `protobuf:"bytes,11,rep,name=large_communities,json=largeCommunities,proto3" json:"large_communities,omitempty"`
-	OriginatorId      uint32                  `protobuf:"varint,12,opt,name=originator_id,json=originatorId,proto3" json:"originator_id,omitempty"`
-	ClusterList       []uint32                `protobuf:"varint,13,rep,packed,name=cluster_list,json=clusterList,proto3" json:"cluster_list,omitempty"`
-	UnknownAttributes []*UnknownPathAttribute `protobuf:"bytes,14,rep,name=unknown_attributes,json=unknownAttributes,proto3" json:"unknown_attributes,omitempty"`
+	PathIdentifier      uint32                  `protobuf:"varint,1,opt,name=path_identifier,json=pathIdentifier,proto3" json:"path_identifier,omitempty"`
+	NextHop             *api.IP                 `protobuf:"bytes,2,opt,name=next_hop,json=nextHop,proto3" json:"next_hop,omitempty"`
+	LocalPref           uint32                  `protobuf:"varint,3,opt,name=local_pref,json=localPref,proto3" json:"local_pref,omitempty"`
+	AsPath              []*ASPathSegment        `protobuf:"bytes,4,rep,name=as_path,json=asPath,proto3" json:"as_path,omitempty"`
+	Origin              uint32                  `protobuf:"varint,5,opt,name=origin,proto3" json:"origin,omitempty"`
+	Med                 uint32                  `protobuf:"varint,6,opt,name=med,proto3" json:"med,omitempty"`
+	Ebgp                bool                    `protobuf:"varint,7,opt,name=ebgp,proto3" json:"ebgp,omitempty"`
+	BgpIdentifier       uint32                  `protobuf:"varint,8,opt,name=bgp_identifier,json=bgpIdentifier,proto3" json:"bgp_identifier,omitempty"`
+	Source              *api.IP                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`
+	Communities         []uint32                `protobuf:"varint,10,rep,packed,name=communities,proto3" json:"communities,omitempty"`
+	LargeCommunities    []*LargeCommunity       `protobuf:"bytes,11,rep,name=large_communities,json=largeCommunities,proto3" json:"large_communities,omitempty"`
+	OriginatorId        uint32                  `protobuf:"varint,12,opt,name=originator_id,json=originatorId,proto3" json:"originator_id,omitempty"`
+	ClusterList         []uint32                `protobuf:"varint,13,rep,packed,name=cluster_list,json=clusterList,proto3" json:"cluster_list,omitempty"`
+	UnknownAttributes   []*UnknownPathAttribute `protobuf:"bytes,14,rep,name=unknown_attributes,json=unknownAttributes,proto3" json:"unknown_attributes,omitempty"`
+	PmsiTunnel          *PMSITunnel             `protobuf:"bytes,15,opt,name=pmsi_tunnel,json=pmsiTunnel,proto3" json:"pmsi_tunnel,omitempty"`
+	BgpLsAttribute      []byte                  `protobuf:"bytes,16,opt,name=bgp_ls_attribute,json=bgpLsAttribute,proto3" json:"bgp_ls_attribute,omitempty"`
+	ExtendedCommunities []*ExtendedCommunity    `protobuf:"bytes,17,rep,name=extended_communities,json=extendedCommunities,proto3" json:"extended_communities,omitempty"`
+	Aigp                uint64                  `protobuf:"varint,18,opt,name=aigp,proto3" json:"aigp,omitempty"`
+	AigpPresent         bool                    `protobuf:"varint,19,opt,name=aigp_present,json=aigpPresent,proto3" json:"aigp_present,omitempty"`
+	HasMed              bool                    `protobuf:"varint,20,opt,name=has_med,json=hasMed,proto3" json:"has_med,omitempty"`
+	NextHopLinkLocal    *api.IP                 `protobuf:"bytes,21,opt,name=next_hop_link_local,json=nextHopLinkLocal,proto3" json:"next_hop_link_local,omitempty"`
 }
 
 func (x *BGPPath) Reset() {
@@ -383,6 +390,126 @@ func (x *BGPPath) GetUnknownAttributes() []*UnknownPathAttribute {
 	return nil
 }
 
+func (x *BGPPath) GetPmsiTunnel() *PMSITunnel {
+	if x != nil {
+		return x.PmsiTunnel
+	}
+	return nil
+}
+
+func (x *BGPPath) GetBgpLsAttribute() []byte {
+	if x != nil {
+		return x.BgpLsAttribute
+	}
+	return nil
+}
+
+func (x *BGPPath) GetExtendedCommunities() []*ExtendedCommunity {
+	if x != nil {
+		return x.ExtendedCommunities
+	}
+	return nil
+}
+
+func (x *BGPPath) GetAigp() uint64 {
+	if x != nil {
+		return x.Aigp
+	}
+	return 0
+}
+
+func (x *BGPPath) GetAigpPresent() bool {
+	if x != nil {
+		return x.AigpPresent
+	}
+	return false
+}
+
+func (x *BGPPath) GetHasMed() bool {
+	if x != nil {
+		return x.HasMed
+	}
+	return false
+}
+
+func (x *BGPPath) GetNextHopLinkLocal() *api.IP {
+	if x != nil {
+		return x.NextHopLinkLocal
+	}
+	return nil
+}
+
+type PMSITunnel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flags            uint32 `protobuf:"varint,1,opt,name=flags,proto3" json:"flags,omitempty"`
+	TunnelType       uint32 `protobuf:"varint,2,opt,name=tunnel_type,json=tunnelType,proto3" json:"tunnel_type,omitempty"`
+	Label            uint32 `protobuf:"varint,3,opt,name=label,proto3" json:"label,omitempty"`
+	TunnelIdentifier []byte `protobuf:"bytes,4,opt,name=tunnel_identifier,json=tunnelIdentifier,proto3" json:"tunnel_identifier,omitempty"`
+}
+
+func (x *PMSITunnel) Reset() {
+	*x = PMSITunnel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_route_api_route_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PMSITunnel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PMSITunnel) ProtoMessage() {}
+
+func (x *PMSITunnel) ProtoReflect() protoreflect.Message {
+	mi := &file_route_api_route_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PMSITunnel.ProtoReflect.Descriptor instead.
+func (*PMSITunnel) Descriptor() ([]byte, []int) {
+	return file_route_api_route_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PMSITunnel) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *PMSITunnel) GetTunnelType() uint32 {
+	if x != nil {
+		return x.TunnelType
+	}
+	return 0
+}
+
+func (x *PMSITunnel) GetLabel() uint32 {
+	if x != nil {
+		return x.Label
+	}
+	return 0
+}
+
+func (x *PMSITunnel) GetTunnelIdentifier() []byte {
+	if x != nil {
+		return x.TunnelIdentifier
+	}
+	return nil
+}
+
 type ASPathSegment struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -395,7 +522,7 @@ type ASPathSegment struct {
 func (x *ASPathSegment) Reset() {
 	*x = ASPathSegment{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_route_api_route_proto_msgTypes[4]
+		mi := &file_route_api_route_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -408,7 +535,7 @@ func (x *ASPathSegment) String() string {
 func (*ASPathSegment) ProtoMessage() {}
 
 func (x *ASPathSegment) ProtoReflect() protoreflect.Message {
-	mi := &file_route_api_route_proto_msgTypes[4]
+	mi := &file_route_api_route_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,7 +548,7 @@ func (x *ASPathSegment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ASPathSegment.ProtoReflect.Descriptor instead.
 func (*ASPathSegment) Descriptor() ([]byte, []int) {
-	return file_route_api_route_proto_rawDescGZIP(), []int{4}
+	return file_route_api_route_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ASPathSegment) GetAsSequence() bool {
@@ -451,7 +578,7 @@ type LargeCommunity struct {
 func (x *LargeCommunity) Reset() {
 	*x = LargeCommunity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_route_api_route_proto_msgTypes[5]
+		mi := &file_route_api_route_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -464,7 +591,7 @@ func (x *LargeCommunity) String() string {
 func (*LargeCommunity) ProtoMessage() {}
 
 func (x *LargeCommunity) ProtoReflect() protoreflect.Message {
-	mi := &file_route_api_route_proto_msgTypes[5]
+	mi := &file_route_api_route_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -477,7 +604,7 @@ func (x *LargeCommunity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LargeCommunity.ProtoReflect.Descriptor instead.
 func (*LargeCommunity) Descriptor() ([]byte, []int) {
-	return file_route_api_route_proto_rawDescGZIP(), []int{5}
+	return file_route_api_route_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *LargeCommunity) GetGlobalAdministrator() uint32 {
@@ -501,6 +628,69 @@ func (x *LargeCommunity) GetDataPart2() uint32 {
 	return 0
 }
 
+type ExtendedCommunity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    uint32 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	SubType uint32 `protobuf:"varint,2,opt,name=sub_type,json=subType,proto3" json:"sub_type,omitempty"`
+	Value   []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *ExtendedCommunity) Reset() {
+	*x = ExtendedCommunity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_route_api_route_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedCommunity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedCommunity) ProtoMessage() {}
+
+func (x *ExtendedCommunity) ProtoReflect() protoreflect.Message {
+	mi := &file_route_api_route_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedCommunity.ProtoReflect.Descriptor instead.
+func (*ExtendedCommunity) Descriptor() ([]byte, []int) {
+	return file_route_api_route_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ExtendedCommunity) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *ExtendedCommunity) GetSubType() uint32 {
+	if x != nil {
+		return x.SubType
+	}
+	return 0
+}
+
+func (x *ExtendedCommunity) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
 type UnknownPathAttribute struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -516,7 +706,7 @@ type UnknownPathAttribute struct {
 func (x *UnknownPathAttribute) Reset() {
 	*x = UnknownPathAttribute{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_route_api_route_proto_msgTypes[6]
+		mi := &file_route_api_route_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -529,7 +719,7 @@ func (x *UnknownPathAttribute) String() string {
 func (*UnknownPathAttribute) ProtoMessage() {}
 
 func (x *UnknownPathAttribute) ProtoReflect() protoreflect.Message {
-	mi := &file_route_api_route_proto_msgTypes[6]
+	mi := &file_route_api_route_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -542,7 +732,7 @@ func (x *UnknownPathAttribute) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnknownPathAttribute.ProtoReflect.Descriptor instead.
 func (*UnknownPathAttribute) Descriptor() ([]byte, []int) {
-	return file_route_api_route_proto_rawDescGZIP(), []int{6}
+	return file_route_api_route_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UnknownPathAttribute) GetOptional() bool {
@@ -606,7 +796,7 @@ var file_route_api_route_proto_rawDesc = []byte{
 	0x34, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x69, 0x63, 0x50, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a,
 	0x08, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x0b, 0x2e, 0x62, 0x69, 0x6f, 0x2e, 0x6e, 0x65, 0x74, 0x2e, 0x49, 0x50, 0x52, 0x07, 0x6e, 0x65,
-	0x78, 0x74, 0x48, 0x6f, 0x70, 0x22, 0xb8, 0x04, 0x0a, 0x07, 0x42, 0x47, 0x50, 0x50, 0x61, 0x74,
+	0x78, 0x74, 0x48, 0x6f, 0x70, 0x22, 0xf7, 0x06, 0x0a, 0x07, 0x42, 0x47, 0x50, 0x50, 0x61, 0x74,
 	0x68, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
 	0x66, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x70, 0x61, 0x74, 0x68,
 	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x08, 0x6e, 0x65,
@@ -642,32 +832,67 @@ var file_route_api_route_proto_rawDesc = []byte{
 	0x69, 0x6f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x2e, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
 	0x50, 0x61, 0x74, 0x68, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x52, 0x11, 0x75,
 	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73,
-	0x22, 0x44, 0x0a, 0x0d, 0x41, 0x53, 0x50, 0x61, 0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
-	0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x61, 0x73, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d,
-	0x52, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0e, 0x4c, 0x61, 0x72, 0x67, 0x65,
-	0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74, 0x79, 0x12, 0x31, 0x0a, 0x14, 0x67, 0x6c, 0x6f,
-	0x62, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x41,
-	0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a,
-	0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x50, 0x61, 0x72, 0x74, 0x31, 0x12, 0x1d, 0x0a, 0x0a, 0x64,
-	0x61, 0x74, 0x61, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x32, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x09, 0x64, 0x61, 0x74, 0x61, 0x50, 0x61, 0x72, 0x74, 0x32, 0x22, 0x9f, 0x01, 0x0a, 0x14, 0x55,
-	0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62,
-	0x75, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x12,
-	0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x79, 0x70,
-	0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x74, 0x79,
-	0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x29, 0x5a, 0x27,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x69, 0x6f, 0x2d, 0x72,
-	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x2f, 0x62, 0x69, 0x6f, 0x2d, 0x72, 0x64, 0x2f, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x36, 0x0a, 0x0b, 0x70, 0x6d, 0x73, 0x69, 0x5f, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x69, 0x6f, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x2e, 0x50, 0x4d, 0x53, 0x49, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x0a, 0x70, 0x6d,
+	0x73, 0x69, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x28, 0x0a, 0x10, 0x62, 0x67, 0x70, 0x5f,
+	0x6c, 0x73, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0e, 0x62, 0x67, 0x70, 0x4c, 0x73, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x12, 0x4f, 0x0a, 0x14, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x63,
+	0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x62, 0x69, 0x6f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x2e, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74, 0x79, 0x52, 0x13,
+	0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x69, 0x67, 0x70, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x04, 0x61, 0x69, 0x67, 0x70, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x69, 0x67, 0x70, 0x5f,
+	0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61,
+	0x69, 0x67, 0x70, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x61,
+	0x73, 0x5f, 0x6d, 0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x68, 0x61, 0x73,
+	0x4d, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x13, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f,
+	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x62, 0x69, 0x6f, 0x2e, 0x6e, 0x65, 0x74, 0x2e, 0x49, 0x50, 0x52, 0x10, 0x6e,
+	0x65, 0x78, 0x74, 0x48, 0x6f, 0x70, 0x4c, 0x69, 0x6e, 0x6b, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x22,
+	0x86, 0x01, 0x0a, 0x0a, 0x50, 0x4d, 0x53, 0x49, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x14,
+	0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x66,
+	0x6c, 0x61, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x75, 0x6e, 0x6e, 0x65,
+	0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x2b, 0x0a, 0x11, 0x74,
+	0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x22, 0x44, 0x0a, 0x0d, 0x41, 0x53, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x5f,
+	0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x61, 0x73, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x73,
+	0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x04, 0x61, 0x73, 0x6e, 0x73, 0x22, 0x81,
+	0x01, 0x0a, 0x0e, 0x4c, 0x61, 0x72, 0x67, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74,
+	0x79, 0x12, 0x31, 0x0a, 0x14, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x6d, 0x69,
+	0x6e, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x13, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x61, 0x72,
+	0x74, 0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x50, 0x61,
+	0x72, 0x74, 0x31, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x61, 0x72, 0x74,
+	0x32, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x50, 0x61, 0x72,
+	0x74, 0x32, 0x22, 0x58, 0x0a, 0x11, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x43, 0x6f,
+	0x6d, 0x6d, 0x75, 0x6e, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73,
+	0x75, 0x62, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73,
+	0x75, 0x62, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x9f, 0x01, 0x0a,
+	0x14, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x41, 0x74, 0x74, 0x72,
+	0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x79, 0x70, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x74, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x29,
+	0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x69, 0x6f,
+	0x2d, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x2f, 0x62, 0x69, 0x6f, 0x2d, 0x72, 0x64, 0x2f,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -683,36 +908,41 @@ func file_route_api_route_proto_rawDescGZIP() []byte {
 }
 
 var file_route_api_route_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_route_api_route_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_route_api_route_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_route_api_route_proto_goTypes = []interface{}{
 	(Path_Type)(0),               // 0: bio.route.Path.Type
 	(*Route)(nil),                // 1: bio.route.Route
 	(*Path)(nil),                 // 2: bio.route.Path
 	(*StaticPath)(nil),           // 3: bio.route.StaticPath
 	(*BGPPath)(nil),              // 4: bio.route.BGPPath
-	(*ASPathSegment)(nil),        // 5: bio.route.ASPathSegment
-	(*LargeCommunity)(nil),       // 6: bio.route.LargeCommunity
-	(*UnknownPathAttribute)(nil), // 7: bio.route.UnknownPathAttribute
-	(*api.Prefix)(nil),           // 8: bio.net.Prefix
-	(*api.IP)(nil),               // 9: bio.net.IP
+	(*PMSITunnel)(nil),           // 5: bio.route.PMSITunnel
+	(*ASPathSegment)(nil),        // 6: bio.route.ASPathSegment
+	(*LargeCommunity)(nil),       // 7: bio.route.LargeCommunity
+	(*ExtendedCommunity)(nil),    // 8: bio.route.ExtendedCommunity
+	(*UnknownPathAttribute)(nil), // 9: bio.route.UnknownPathAttribute
+	(*api.Prefix)(nil),           // 10: bio.net.Prefix
+	(*api.IP)(nil),               // 11: bio.net.IP
 }
 var file_route_api_route_proto_depIdxs = []int32{
-	8,  // 0: bio.route.Route.pfx:type_name -> bio.net.Prefix
+	10, // 0: bio.route.Route.pfx:type_name -> bio.net.Prefix
 	2,  // 1: bio.route.Route.paths:type_name -> bio.route.Path
 	0,  // 2: bio.route.Path.type:type_name -> bio.route.Path.Type
 	3,  // 3: bio.route.Path.static_path:type_name -> bio.route.StaticPath
 	4,  // 4: bio.route.Path.bgp_path:type_name -> bio.route.BGPPath
-	9,  // 5: bio.route.StaticPath.next_hop:type_name -> bio.net.IP
-	9,  // 6: bio.route.BGPPath.next_hop:type_name -> bio.net.IP
-	5,  // 7: bio.route.BGPPath.as_path:type_name -> bio.route.ASPathSegment
-	9,  // 8: bio.route.BGPPath.source:type_name -> bio.net.IP
-	6,  // 9: bio.route.BGPPath.large_communities:type_name -> bio.route.LargeCommunity
-	7,  // 10: bio.route.BGPPath.unknown_attributes:type_name -> bio.route.UnknownPathAttribute
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	11, // 5: bio.route.StaticPath.next_hop:type_name -> bio.net.IP
+	11, // 6: bio.route.BGPPath.next_hop:type_name -> bio.net.IP
+	6,  // 7: bio.route.BGPPath.as_path:type_name -> bio.route.ASPathSegment
+	11, // 8: bio.route.BGPPath.source:type_name -> bio.net.IP
+	7,  // 9: bio.route.BGPPath.large_communities:type_name -> bio.route.LargeCommunity
+	9,  // 10: bio.route.BGPPath.unknown_attributes:type_name -> bio.route.UnknownPathAttribute
+	5,  // 11: bio.route.BGPPath.pmsi_tunnel:type_name -> bio.route.PMSITunnel
+	8,  // 12: bio.route.BGPPath.extended_communities:type_name -> bio.route.ExtendedCommunity
+	11, // 13: bio.route.BGPPath.next_hop_link_local:type_name -> bio.net.IP
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_route_api_route_proto_init() }
@@ -770,7 +1000,7 @@ func file_route_api_route_proto_init() {
 			}
 		}
 		file_route_api_route_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ASPathSegment); i {
+			switch v := v.(*PMSITunnel); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -782,7 +1012,7 @@ func file_route_api_route_proto_init() {
 			}
 		}
 		file_route_api_route_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LargeCommunity); i {
+			switch v := v.(*ASPathSegment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -794,6 +1024,30 @@ func file_route_api_route_proto_init() {
 			}
 		}
 		file_route_api_route_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LargeCommunity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_route_api_route_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedCommunity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_route_api_route_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UnknownPathAttribute); i {
 			case 0:
 				return &v.state
@@ -812,7 +1066,7 @@ func file_route_api_route_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_route_api_route_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ASPathACLEntry is an entry of an AS path access list
+type ASPathACLEntry struct {
+	Regexp *regexp.Regexp
+	Permit bool
+}
+
+// ASPathACL is an AS path access list. Entries are evaluated in order, the first matching entry decides.
+type ASPathACL []ASPathACLEntry
+
+// NewASPathACLEntry creates a new ASPathACLEntry for the regular expression expr
+func NewASPathACLEntry(expr string, permit bool) (ASPathACLEntry, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ASPathACLEntry{}, fmt.Errorf("unable to compile regexp %q: %w", expr, err)
+	}
+
+	return ASPathACLEntry{
+		Regexp: re,
+		Permit: permit,
+	}, nil
+}
+
+// ASPathMatches checks if the AS path matches re. AS_SETs are matched as "(a b)", see ASPath.ACLString.
+func (b *BGPPath) ASPathMatches(re *regexp.Regexp) bool {
+	return re.MatchString(b.ASPath.ACLString())
+}
+
+// EvaluateASPathACL evaluates acl against the AS path of b. The first matching entry
+// decides. If no entry matches the path is denied.
+func (b *BGPPath) EvaluateASPathACL(acl ASPathACL) bool {
+	for _, e := range acl {
+		if b.ASPathMatches(e.Regexp) {
+			return e.Permit
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package route
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateASPathACL(t *testing.T) {
+	mustEntry := func(expr string, permit bool) ASPathACLEntry {
+		e, err := NewASPathACLEntry(expr, permit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return e
+	}
+
+	acl := ASPathACL{
+		mustEntry("^3320 ", true),
+		mustEntry(" 64512$", false),
+		mustEntry("^201701$", true),
+	}
+
+	newPath := func(asns ...uint32) *BGPPath {
+		return &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name:     "First entry permits",
+			path:     newPath(3320, 201701, 64512),
+			expected: true,
+		},
+		{
+			name:     "Second entry denies",
+			path:     newPath(6939, 64512),
+			expected: false,
+		},
+		{
+			name:     "Third entry permits",
+			path:     newPath(201701),
+			expected: true,
+		},
+		{
+			name:     "Implicit deny",
+			path:     newPath(6939, 13335),
+			expected: false,
+		},
+		{
+			name:     "Empty AS path",
+			path:     &BGPPath{},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.EvaluateASPathACL(acl), test.name)
+	}
+}
+
+func TestASPathMatchesASSet(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320},
+			},
+			{
+				Type: types.ASSet,
+				ASNs: []uint32{1, 2},
+			},
+		},
+	}
+
+	assert.True(t, p.ASPathMatches(regexp.MustCompile(`^3320 \(1 2\)$`)))
+}
+
+func TestNewASPathACLEntry(t *testing.T) {
+	_, err := NewASPathACLEntry("(", true)
+	assert.Error(t, err)
+}
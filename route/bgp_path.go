@@ -2,10 +2,17 @@ package route
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/bio-routing/tflow2/convert"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 
 	bnet "github.com/bio-routing/bio-rd/net"
 	"github.com/bio-routing/bio-rd/protocols/bgp/types"
@@ -14,28 +21,65 @@ import (
 
 // BGPPath represents a set of BGP path attributes
 type BGPPath struct {
-	BGPPathA          *BGPPathA
-	ASPath            *types.ASPath
-	ClusterList       *types.ClusterList
-	Communities       *types.Communities
-	LargeCommunities  *types.LargeCommunities
-	UnknownAttributes []types.UnknownPathAttribute
-	PathIdentifier    uint32
-	ASPathLen         uint16
+	BGPPathA            *BGPPathA
+	ASPath              *types.ASPath
+	ClusterList         *types.ClusterList
+	Communities         *types.Communities
+	LargeCommunities    *types.LargeCommunities
+	ExtendedCommunities *[]types.ExtendedCommunity
+	UnknownAttributes   []types.UnknownPathAttribute
+	PMSITunnel          *types.PMSITunnel
+	BGPLSAttribute      *types.BGPLSAttribute
+	PathIdentifier      uint32
+	ASPathLen           uint16
+	AFI                 uint16 // Address family of the NLRI the path belongs to. 0 if unknown.
+	SAFI                uint8
+	ResolvedNextHop     *bnet.IP       // Next hop after recursive resolution. Not considered for path selection.
+	SourceProtocol      SourceProtocol // Protocol the path was originally learned from. Not part of the paths hash.
+	ReceivedAt          time.Time      // Time the path was received. Not part of the paths hash.
+	CommunitiesSetBy    string         // Policy/term that last modified the communities. Not part of the paths hash.
 }
 
 // BGPPathA represents cachable BGP path attributes
 type BGPPathA struct {
-	NextHop         *bnet.IP
-	Source          *bnet.IP
-	LocalPref       uint32
-	MED             uint32
-	BGPIdentifier   uint32
-	OriginatorID    uint32
-	Aggregator      *types.Aggregator
-	EBGP            bool
-	AtomicAggregate bool
-	Origin          uint8
+	NextHop          *bnet.IP
+	NextHopLinkLocal *bnet.IP // IPv6 link local next hop (RFC2545). Path selection only considers NextHop.
+	Source           *bnet.IP
+	LocalPref        uint32
+	MED              uint32
+	HasMED           bool // MULTI_EXIT_DISC is present, even if zero. See MEDPresent.
+	BGPIdentifier    uint32
+	OriginatorID     uint32
+	Aggregator       *types.Aggregator
+	EBGP             bool
+	AtomicAggregate  bool
+	Origin           uint8
+	IGPMetric        uint32 // IGP metric to the next hop as set by the resolver (see ResolveNextHop). Not part of the paths hash.
+	AIGP             uint64 // Accumulated IGP metric (RFC7311). Only valid if AIGPPresent is set.
+	AIGPPresent      bool
+}
+
+// MEDPresent checks if the path carries a MULTI_EXIT_DISC. A non zero MED is always considered present.
+func (b *BGPPathA) MEDPresent() bool {
+	return b.HasMED || b.MED != 0
+}
+
+// compareAIGP compares the AIGP metrics of b and c. It returns 1 if b has the lower metric, -1 if c has.
+// Unless both paths carry AIGP 0 is returned, so paths without AIGP are not reordered.
+func (b *BGPPathA) compareAIGP(c *BGPPathA) int8 {
+	if !b.AIGPPresent || !c.AIGPPresent {
+		return 0
+	}
+
+	if b.AIGP < c.AIGP {
+		return 1
+	}
+
+	if b.AIGP > c.AIGP {
+		return -1
+	}
+
+	return 0
 }
 
 // NewBGPPathA creates a new BGPPathA
@@ -48,63 +92,179 @@ func NewBGPPathA() *BGPPathA {
 }
 
 func (b *BGPPathA) Dedup() *BGPPathA {
+	if b.NextHopLinkLocal != nil {
+		b.NextHopLinkLocal = b.NextHopLinkLocal.Dedup()
+	}
+
 	return bgpC.get(b)
 }
 
 func (b *BGPPath) Dedup() *BGPPath {
 	b.BGPPathA = b.BGPPathA.Dedup()
+	b.ClusterList = internClusterList(b.ClusterList)
 	return b
 }
 
-// ToProto converts BGPPath to proto BGPPath
+const (
+	afiIPv4     = 1
+	afiIPv6     = 2
+	safiUnicast = 1
+)
+
+// isIPv6Family checks if the path belongs to an IPv6 NLRI. If the family is unknown it is inferred from the next hop.
+func (b *BGPPath) isIPv6Family() bool {
+	if b.AFI != 0 {
+		return b.AFI == afiIPv6
+	}
+
+	return b.BGPPathA.NextHop != nil && !b.BGPPathA.NextHop.IsIPv4()
+}
+
+// NextHopFamilyMatchesNLRI checks if the address family of the next hop matches the AFI of the path.
+// IPv4 next hops of IPv6 NLRIs are accepted as they are encoded as IPv4-mapped IPv6 addresses (RFC4291 2.5.5.2),
+// as are IPv4-mapped IPv6 next hops of IPv4 NLRIs. Paths of unknown family always match.
+func (b *BGPPath) NextHopFamilyMatchesNLRI() bool {
+	switch b.AFI {
+	case afiIPv4:
+		return b.BGPPathA.NextHop != nil && (b.BGPPathA.NextHop.IsIPv4() || isIPv4MappedIPv6(b.BGPPathA.NextHop))
+	case afiIPv6:
+		return b.BGPPathA.NextHop != nil
+	}
+
+	return true
+}
+
+func isIPv4MappedIPv6(addr *bnet.IP) bool {
+	return !addr.IsIPv4() && addr.Higher() == 0 && addr.Lower()>>32 == 0xffff
+}
+
+// ToProto converts BGPPath to proto BGPPath. Communities and large communities are sorted to get a
+// stable output regardless of the order they were received in. The CLUSTER_LIST keeps its order.
 func (b *BGPPath) ToProto() *api.BGPPath {
 	if b == nil {
 		return nil
 	}
 
-	a := &api.BGPPath{
-		PathIdentifier:    b.PathIdentifier,
-		NextHop:           b.BGPPathA.NextHop.ToProto(),
-		LocalPref:         b.BGPPathA.LocalPref,
-		Origin:            uint32(b.BGPPathA.Origin),
-		Med:               b.BGPPathA.MED,
-		Ebgp:              b.BGPPathA.EBGP,
-		BgpIdentifier:     b.BGPPathA.BGPIdentifier,
-		Source:            b.BGPPathA.Source.ToProto(),
-		UnknownAttributes: make([]*api.UnknownPathAttribute, len(b.UnknownAttributes)),
-		OriginatorId:      b.BGPPathA.OriginatorID,
+	a := &api.BGPPath{}
+	b.ToProtoInto(a)
+	return a
+}
+
+// ToProtoInto converts BGPPath into the caller provided proto BGPPath dst. Slices of dst are reused
+// if their capacity allows, so dst can be recycled e.g. when streaming a RIB. If b is nil, dst is reset.
+func (b *BGPPath) ToProtoInto(dst *api.BGPPath) {
+	if b == nil {
+		proto.Reset(dst)
+		return
 	}
 
+	dst.PathIdentifier = b.PathIdentifier
+	dst.NextHop = b.BGPPathA.NextHop.ToProto()
+	dst.LocalPref = b.BGPPathA.LocalPref
+	dst.Origin = uint32(b.BGPPathA.Origin)
+	dst.Med = b.BGPPathA.MED
+	dst.HasMed = b.BGPPathA.HasMED
+	dst.NextHopLinkLocal = nil
+	if b.BGPPathA.NextHopLinkLocal != nil {
+		dst.NextHopLinkLocal = b.BGPPathA.NextHopLinkLocal.ToProto()
+	}
+	dst.Ebgp = b.BGPPathA.EBGP
+	dst.BgpIdentifier = b.BGPPathA.BGPIdentifier
+	dst.Source = b.BGPPathA.Source.ToProto()
+	dst.OriginatorId = b.BGPPathA.OriginatorID
+	dst.Aigp = b.BGPPathA.AIGP
+	dst.AigpPresent = b.BGPPathA.AIGPPresent
+
+	dst.AsPath = nil
 	if b.ASPath != nil {
-		a.AsPath = b.ASPath.ToProto()
+		dst.AsPath = b.ASPath.ToProto()
 	}
 
-	if a.ClusterList != nil {
-		a.ClusterList = make([]uint32, len(*b.ClusterList))
-		for i := range *b.ClusterList {
-			a.ClusterList[i] = (*b.ClusterList)[i]
-		}
+	dst.PmsiTunnel = b.PMSITunnel.ToProto()
+
+	dst.BgpLsAttribute = nil
+	if b.BGPLSAttribute != nil {
+		dst.BgpLsAttribute = append([]byte{}, b.BGPLSAttribute.Value...)
 	}
 
+	dst.ClusterList = clusterListToProto(dst.ClusterList, b.ClusterList)
+
+	communities := dst.Communities
+	dst.Communities = nil
 	if b.Communities != nil {
-		a.Communities = make([]uint32, len(*b.Communities))
-		for i := range *b.Communities {
-			a.Communities[i] = (*b.Communities)[i]
-		}
+		dst.Communities = append(reuseUint32Slice(communities, len(*b.Communities)), *b.Communities...)
+		sortUint32s(dst.Communities)
 	}
 
+	largeCommunities := dst.LargeCommunities
+	dst.LargeCommunities = nil
 	if b.LargeCommunities != nil {
-		a.LargeCommunities = make([]*api.LargeCommunity, len(*b.LargeCommunities))
+		if cap(largeCommunities) < len(*b.LargeCommunities) {
+			largeCommunities = make([]*api.LargeCommunity, 0, len(*b.LargeCommunities))
+		}
+
+		dst.LargeCommunities = largeCommunities[:0]
 		for i := range *b.LargeCommunities {
-			a.LargeCommunities[i] = (*b.LargeCommunities)[i].ToProto()
+			dst.LargeCommunities = append(dst.LargeCommunities, (*b.LargeCommunities)[i].ToProto())
+		}
+
+		sortProtoLargeCommunities(dst.LargeCommunities)
+	}
+
+	extendedCommunities := dst.ExtendedCommunities
+	dst.ExtendedCommunities = nil
+	if b.ExtendedCommunities != nil {
+		if cap(extendedCommunities) < len(*b.ExtendedCommunities) {
+			extendedCommunities = make([]*api.ExtendedCommunity, 0, len(*b.ExtendedCommunities))
+		}
+
+		dst.ExtendedCommunities = extendedCommunities[:0]
+		for i := range *b.ExtendedCommunities {
+			dst.ExtendedCommunities = append(dst.ExtendedCommunities, (*b.ExtendedCommunities)[i].ToProto())
 		}
 	}
 
+	if cap(dst.UnknownAttributes) < len(b.UnknownAttributes) || dst.UnknownAttributes == nil {
+		dst.UnknownAttributes = make([]*api.UnknownPathAttribute, 0, len(b.UnknownAttributes))
+	}
+
+	dst.UnknownAttributes = dst.UnknownAttributes[:0]
 	for i := range b.UnknownAttributes {
-		a.UnknownAttributes[i] = b.UnknownAttributes[i].ToProto()
+		dst.UnknownAttributes = append(dst.UnknownAttributes, b.UnknownAttributes[i].ToProto())
+	}
+}
+
+func sortProtoLargeCommunities(x []*api.LargeCommunity) {
+	sort.Slice(x, func(i, j int) bool {
+		if x[i].GlobalAdministrator != x[j].GlobalAdministrator {
+			return x[i].GlobalAdministrator < x[j].GlobalAdministrator
+		}
+
+		if x[i].DataPart1 != x[j].DataPart1 {
+			return x[i].DataPart1 < x[j].DataPart1
+		}
+
+		return x[i].DataPart2 < x[j].DataPart2
+	})
+}
+
+// clusterListToProto converts cl reusing the capacity of dst. The CLUSTER_LIST is exported in received
+// order, a nil cluster list is converted to nil.
+func clusterListToProto(dst []uint32, cl *types.ClusterList) []uint32 {
+	if cl == nil {
+		return nil
 	}
 
-	return a
+	return append(reuseUint32Slice(dst, len(*cl)), *cl...)
+}
+
+// reuseUint32Slice returns s truncated to length 0 if it can hold n elements, a new slice otherwise
+func reuseUint32Slice(s []uint32, n int) []uint32 {
+	if cap(s) < n || s == nil {
+		return make([]uint32, 0, n)
+	}
+
+	return s[:0]
 }
 
 // BGPPathFromProtoBGPPath converts a proto BGPPath to BGPPath
@@ -116,18 +276,33 @@ func BGPPathFromProtoBGPPath(pb *api.BGPPath, dedup bool) *BGPPath {
 			OriginatorID:  pb.OriginatorId,
 			Origin:        uint8(pb.Origin),
 			MED:           pb.Med,
+			HasMED:        pb.HasMed,
 			EBGP:          pb.Ebgp,
 			BGPIdentifier: pb.BgpIdentifier,
 			Source:        bnet.IPFromProtoIP(pb.Source),
+			AIGP:          pb.Aigp,
+			AIGPPresent:   pb.AigpPresent,
 		},
 		PathIdentifier: pb.PathIdentifier,
 		ASPath:         types.ASPathFromProtoASPath(pb.AsPath),
+		PMSITunnel:     types.PMSITunnelFromProtoPMSITunnel(pb.PmsiTunnel),
+	}
+	p.ASPathLen = p.ASPath.Length()
+
+	if pb.NextHopLinkLocal != nil {
+		p.BGPPathA.NextHopLinkLocal = bnet.IPFromProtoIP(pb.NextHopLinkLocal)
 	}
 
 	if dedup {
 		p = p.Dedup()
 	}
 
+	if pb.BgpLsAttribute != nil {
+		p.BGPLSAttribute = &types.BGPLSAttribute{
+			Value: append([]byte{}, pb.BgpLsAttribute...),
+		}
+	}
+
 	communities := make(types.Communities, len(pb.Communities))
 	p.Communities = &communities
 
@@ -156,61 +331,358 @@ func BGPPathFromProtoBGPPath(pb *api.BGPPath, dedup bool) *BGPPath {
 		(*p.ClusterList)[i] = pb.ClusterList[i]
 	}
 
+	if len(pb.ExtendedCommunities) > 0 {
+		extCommunities := make([]types.ExtendedCommunity, len(pb.ExtendedCommunities))
+		for i := range pb.ExtendedCommunities {
+			extCommunities[i] = types.ExtendedCommunityFromProtoExtendedCommunity(pb.ExtendedCommunities[i])
+		}
+		p.ExtendedCommunities = &extCommunities
+	}
+
+	if dedup {
+		p.ClusterList = internClusterList(p.ClusterList)
+	}
+
 	return p
 }
 
+// BGPPathsFromProto converts proto BGPPaths to BGPPaths. Malformed paths are skipped and an error
+// naming the index of the path is returned for each of them.
+func BGPPathsFromProto(pbs []*api.BGPPath) ([]*BGPPath, []error) {
+	paths := make([]*BGPPath, 0, len(pbs))
+	var errs []error
+
+	for i, pb := range pbs {
+		if err := validateProtoBGPPath(pb); err != nil {
+			errs = append(errs, fmt.Errorf("path %d: %w", i, err))
+			continue
+		}
+
+		paths = append(paths, BGPPathFromProtoBGPPath(pb, true))
+	}
+
+	return paths, errs
+}
+
+func validateProtoBGPPath(pb *api.BGPPath) error {
+	if pb == nil {
+		return fmt.Errorf("path is nil")
+	}
+
+	if pb.NextHop == nil {
+		return fmt.Errorf("next hop is nil")
+	}
+
+	if pb.Source == nil {
+		return fmt.Errorf("source is nil")
+	}
+
+	for i := range pb.AsPath {
+		if pb.AsPath[i] == nil {
+			return fmt.Errorf("AS path segment %d is nil", i)
+		}
+	}
+
+	for i := range pb.LargeCommunities {
+		if pb.LargeCommunities[i] == nil {
+			return fmt.Errorf("large community %d is nil", i)
+		}
+	}
+
+	for i := range pb.UnknownAttributes {
+		if pb.UnknownAttributes[i] == nil {
+			return fmt.Errorf("unknown attribute %d is nil", i)
+		}
+	}
+
+	return nil
+}
+
+// Attribute categories used by AttributeSizes
+const (
+	AttributeSizeBase                = "base"
+	AttributeSizeNextHop             = "next-hop"
+	AttributeSizeASPath              = "as-path"
+	AttributeSizeCommunities         = "communities"
+	AttributeSizeLargeCommunities    = "large-communities"
+	AttributeSizeExtendedCommunities = "extended-communities"
+	AttributeSizeClusterList         = "cluster-list"
+	AttributeSizeOriginatorID        = "originator-id"
+	AttributeSizeAtomicAggregate     = "atomic-aggregate"
+	AttributeSizePMSITunnel          = "pmsi-tunnel"
+	AttributeSizeBGPLS               = "bgp-ls"
+	AttributeSizeAIGP                = "aigp"
+	AttributeSizeUnknown             = "unknown"
+)
+
+type attributeSizes struct {
+	base             uint16
+	nextHop          uint16
+	asPath           uint16
+	communities      uint16
+	largeCommunities uint16
+	extCommunities   uint16
+	clusterList      uint16
+	originatorID     uint16
+	atomicAggregate  uint16
+	pmsiTunnel       uint16
+	bgpLS            uint16
+	aigp             uint16
+	unknown          uint16
+}
+
+func (a attributeSizes) sum() uint16 {
+	return a.base + a.nextHop + a.asPath + a.communities + a.largeCommunities + a.extCommunities + a.clusterList + a.originatorID + a.atomicAggregate + a.pmsiTunnel + a.bgpLS + a.aigp + a.unknown
+}
+
+// attributeHeaderLen gets the length of flags, type code and length field of an attribute with a value of
+// valueLen bytes. Values exceeding 255 bytes require the extended length flag and a two byte length field.
+func attributeHeaderLen(valueLen int) uint16 {
+	if valueLen > math.MaxUint8 {
+		return 4
+	}
+
+	return 3
+}
+
 // Length get's the length of serialized path
 func (b *BGPPath) Length() uint16 {
-	asPathLen := uint16(3)
+	return b.attributeSizes().sum()
+}
+
+// AttributeSizes gets the contribution of each attribute category to the length of the serialized path.
+// The values sum up to Length().
+func (b *BGPPath) AttributeSizes() map[string]uint16 {
+	s := b.attributeSizes()
+
+	return map[string]uint16{
+		AttributeSizeBase:                s.base,
+		AttributeSizeNextHop:             s.nextHop,
+		AttributeSizeASPath:              s.asPath,
+		AttributeSizeCommunities:         s.communities,
+		AttributeSizeLargeCommunities:    s.largeCommunities,
+		AttributeSizeExtendedCommunities: s.extCommunities,
+		AttributeSizeClusterList:         s.clusterList,
+		AttributeSizeOriginatorID:        s.originatorID,
+		AttributeSizeAtomicAggregate:     s.atomicAggregate,
+		AttributeSizePMSITunnel:          s.pmsiTunnel,
+		AttributeSizeBGPLS:               s.bgpLS,
+		AttributeSizeAIGP:                s.aigp,
+		AttributeSizeUnknown:             s.unknown,
+	}
+}
+
+func (b *BGPPath) attributeSizes() attributeSizes {
+	s := attributeSizes{
+		base:    3*7 + 4,
+		nextHop: b.nextHopSize(),
+	}
+
+	// Every segment starts with its type and the number of ASNs
+	asPathLen := 0
 	for _, segment := range *b.ASPath {
-		asPathLen++
-		asPathLen += uint16(4 * len(segment.ASNs))
+		asPathLen += 2 + 4*len(segment.ASNs)
 	}
+	s.asPath = attributeHeaderLen(asPathLen) + uint16(asPathLen)
 
-	communitiesLen := uint16(0)
-	if b.Communities != nil && len(*b.Communities) != 0 {
-		communitiesLen += 3 + uint16(len(*b.Communities)*4)
+	for _, chunk := range b.SplitCommunitiesForWire() {
+		s.communities += attributeHeaderLen(len(chunk)*4) + uint16(len(chunk)*4)
 	}
 
-	largeCommunitiesLen := uint16(0)
 	if b.LargeCommunities != nil && len(*b.LargeCommunities) != 0 {
-		largeCommunitiesLen += 3 + uint16(len(*b.LargeCommunities)*12)
+		l := len(*b.LargeCommunities) * 12
+		s.largeCommunities += attributeHeaderLen(l) + uint16(l)
+	}
+
+	if b.ExtendedCommunities != nil && len(*b.ExtendedCommunities) != 0 {
+		l := len(*b.ExtendedCommunities) * types.ExtendedCommunityLen
+		s.extCommunities += attributeHeaderLen(l) + uint16(l)
 	}
 
-	clusterListLen := uint16(0)
 	if b.ClusterList != nil && len(*b.ClusterList) != 0 {
-		clusterListLen += 3 + uint16(len(*b.ClusterList)*4)
+		l := len(*b.ClusterList) * 4
+		s.clusterList += attributeHeaderLen(l) + uint16(l)
 	}
 
-	unknownAttributesLen := uint16(0)
-	if b.UnknownAttributes != nil {
-		for _, unknownAttr := range b.UnknownAttributes {
-			unknownAttributesLen += unknownAttr.WireLength()
-		}
+	if b.PMSITunnel != nil {
+		s.pmsiTunnel = attributeHeaderLen(int(b.PMSITunnel.Length())) + b.PMSITunnel.Length()
+	}
+
+	if b.BGPLSAttribute != nil {
+		s.bgpLS = b.BGPLSAttribute.WireLength()
+	}
+
+	if b.BGPPathA.AIGPPresent {
+		s.aigp = attributeHeaderLen(types.AIGPTLVLen) + types.AIGPTLVLen
+	}
+
+	for _, unknownAttr := range b.UnknownAttributes {
+		s.unknown += unknownAttr.WireLength()
 	}
 
-	originatorID := uint16(0)
 	if b.BGPPathA.OriginatorID != 0 {
-		originatorID = 4
+		s.originatorID = 4
+	}
+
+	if b.BGPPathA.AtomicAggregate {
+		s.atomicAggregate = 3
+	}
+
+	return s
+}
+
+// nextHopSize gets the size of the NEXT_HOP attribute. Paths of other families than IPv4 unicast carry their
+// next hop in MP_REACH_NLRI instead, which is sized without its NLRI.
+func (b *BGPPath) nextHopSize() uint16 {
+	if !b.isIPv6Family() && (b.SAFI == 0 || b.SAFI == safiUnicast) {
+		return 3 + 4
+	}
+
+	// AFI, SAFI, next hop length and reserved byte
+	l := 2 + 1 + 1 + 1
+	if b.isIPv6Family() {
+		l += 16
+		if b.BGPPathA.NextHopLinkLocal != nil {
+			l += 16
+		}
+	} else {
+		l += 4
 	}
 
-	return communitiesLen + largeCommunitiesLen + 4*7 + 4 + originatorID + asPathLen + unknownAttributesLen
+	return attributeHeaderLen(l) + uint16(l)
 }
 
 // ECMP determines if routes b and c are euqal in terms of ECMP
+// Only the AS path lengths have to be equal (as-path multipath-relax).
 func (b *BGPPath) ECMP(c *BGPPath) bool {
+	return b.ECMPWithOptions(c, BGPSelectOptions{
+		ASPathMultipathRelax: true,
+	})
+}
+
+// ECMPWithOptions works like ECMP but requires identical AS paths unless opts.ASPathMultipathRelax is set
+func (b *BGPPath) ECMPWithOptions(c *BGPPath, opts BGPSelectOptions) bool {
+	if !opts.ASPathMultipathRelax && !b.ASPath.Compare(c.ASPath) {
+		return false
+	}
+
 	return b.BGPPathA.LocalPref == c.BGPPathA.LocalPref &&
 		b.ASPathLen == c.ASPathLen &&
+		b.BGPPathA.compareAIGP(c.BGPPathA) == 0 &&
 		b.BGPPathA.MED == c.BGPPathA.MED &&
 		b.BGPPathA.Origin == c.BGPPathA.Origin
 }
 
+// ECMPCriteria selects the attributes which must be equal for paths to be considered ECMP
+type ECMPCriteria struct {
+	LocalPref  bool
+	ASPathLen  bool
+	Origin     bool
+	MED        bool
+	AIGP       bool
+	NeighborAS bool
+}
+
+// ECMPWith determines if b and other are ECMP considering only the attributes selected by crit
+func (b *BGPPath) ECMPWith(other *BGPPath, crit ECMPCriteria) bool {
+	if crit.LocalPref && b.BGPPathA.LocalPref != other.BGPPathA.LocalPref {
+		return false
+	}
+
+	if crit.ASPathLen && b.ASPathLen != other.ASPathLen {
+		return false
+	}
+
+	if crit.Origin && b.BGPPathA.Origin != other.BGPPathA.Origin {
+		return false
+	}
+
+	if crit.MED && b.BGPPathA.MED != other.BGPPathA.MED {
+		return false
+	}
+
+	if crit.AIGP && b.BGPPathA.compareAIGP(other.BGPPathA) != 0 {
+		return false
+	}
+
+	if crit.NeighborAS {
+		asnB, okB := b.NeighborAS()
+		asnOther, okOther := other.NeighborAS()
+		if asnB != asnOther || okB != okOther {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SameForwarding checks if paths b and c result in the same forwarding action. Paths
+// without a resolved next hop are compared by their next hop.
+func (b *BGPPath) SameForwarding(c *BGPPath) bool {
+	return b.forwardingNextHop().Compare(c.forwardingNextHop()) == 0
+}
+
+func (b *BGPPath) forwardingNextHop() *bnet.IP {
+	if b.ResolvedNextHop != nil {
+		return b.ResolvedNextHop
+	}
+
+	return b.BGPPathA.NextHop
+}
+
+// NextHopReachable checks if the next hop of b is reachable according to resolver.
+// Paths with an unreachable next hop must not be considered usable (RFC4271 9.1.2).
+// If resolver is nil the next hop is considered reachable. See NextHopReachableFunc.
+func (b *BGPPath) NextHopReachable(resolver func(bnet.IP) bool) bool {
+	if b.BGPPathA.NextHop == nil {
+		return false
+	}
+
+	if resolver == nil {
+		return true
+	}
+
+	return resolver(*b.BGPPathA.NextHop)
+}
+
+// NextHopInPrefix checks if the next hop of b lies within p. A next hop within the NLRI it is
+// advertised for is suspicious for paths not describing a connected network.
+func (b *BGPPath) NextHopInPrefix(p bnet.Prefix) bool {
+	if b.BGPPathA.NextHop == nil || b.BGPPathA.NextHop.IsIPv4() != p.Addr().IsIPv4() {
+		return false
+	}
+
+	nh := b.BGPPathA.NextHop.Mask(p.Pfxlen())
+	addr := p.Addr().Mask(p.Pfxlen())
+	return nh.Compare(&addr) == 0
+}
+
+// EffectiveNextHop resolves the next hop of b using resolver. resolver is expected to return the next hop
+// of the path the next hop of b is reachable via. Only one level of recursion is followed.
+func (b *BGPPath) EffectiveNextHop(resolver func(bnet.IP) (*bnet.IP, bool)) (*bnet.IP, bool) {
+	if b.BGPPathA.NextHop == nil || resolver == nil {
+		return nil, false
+	}
+
+	nh, ok := resolver(*b.BGPPathA.NextHop)
+	if !ok || nh == nil {
+		return nil, false
+	}
+
+	return nh, true
+}
+
 // Compare checks if paths are the same
 func (b *BGPPath) Compare(c *BGPPath) bool {
 	if b.PathIdentifier != c.PathIdentifier {
 		return false
 	}
 
+	return b.AttributesEqual(c)
+}
+
+// AttributesEqual checks if the path attributes of b and c are the same. The path identifier is not considered.
+func (b *BGPPath) AttributesEqual(c *BGPPath) bool {
 	if !b.BGPPathA.compare(c.BGPPathA) {
 		return false
 	}
@@ -231,6 +703,18 @@ func (b *BGPPath) Compare(c *BGPPath) bool {
 		return false
 	}
 
+	if !b.compareExtendedCommunities(c) {
+		return false
+	}
+
+	if !b.PMSITunnel.Compare(c.PMSITunnel) {
+		return false
+	}
+
+	if !b.BGPLSAttribute.Compare(c.BGPLSAttribute) {
+		return false
+	}
+
 	return true
 }
 
@@ -316,6 +800,25 @@ func (b *BGPPath) compareLargeCommunities(c *BGPPath) bool {
 	return true
 }
 
+func (b *BGPPath) compareExtendedCommunities(c *BGPPath) bool {
+	if b.ExtendedCommunities == nil || c.ExtendedCommunities == nil {
+		return (b.ExtendedCommunities == nil || len(*b.ExtendedCommunities) == 0) &&
+			(c.ExtendedCommunities == nil || len(*c.ExtendedCommunities) == 0)
+	}
+
+	if len(*b.ExtendedCommunities) != len(*c.ExtendedCommunities) {
+		return false
+	}
+
+	for i := range *b.ExtendedCommunities {
+		if (*b.ExtendedCommunities)[i] != (*c.ExtendedCommunities)[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (b *BGPPath) compareUnknownAttributes(c *BGPPath) bool {
 	if len(b.UnknownAttributes) != len(c.UnknownAttributes) {
 		return false
@@ -335,6 +838,12 @@ func (b *BGPPathA) compare(c *BGPPathA) bool {
 		return false
 	}
 
+	if b.NextHopLinkLocal != nil || c.NextHopLinkLocal != nil {
+		if b.NextHopLinkLocal == nil || c.NextHopLinkLocal == nil || b.NextHopLinkLocal.Compare(c.NextHopLinkLocal) != 0 {
+			return false
+		}
+	}
+
 	if b.Source.Compare(c.Source) != 0 {
 		return false
 	}
@@ -347,6 +856,14 @@ func (b *BGPPathA) compare(c *BGPPathA) bool {
 		return false
 	}
 
+	if b.MEDPresent() != c.MEDPresent() {
+		return false
+	}
+
+	if b.AIGPPresent != c.AIGPPresent || b.AIGP != c.AIGP {
+		return false
+	}
+
 	if b.Aggregator != nil || c.Aggregator != nil {
 		if b.Aggregator != nil && c.Aggregator != nil {
 			if *b.Aggregator != *c.Aggregator {
@@ -369,46 +886,244 @@ func (b *BGPPath) Equal(c *BGPPath) bool {
 	return b.Select(c) == 0
 }
 
-// Select returns negative if b < c, 0 if paths are equal, positive if b > c
-func (b *BGPPath) Select(c *BGPPath) int8 {
-	if c.BGPPathA.LocalPref < b.BGPPathA.LocalPref {
-		return 1
+// PreferenceFromCommunities returns the highest preference assigned in table to any of the paths communities.
+// The bool is false if none of the communities is in table.
+func (b *BGPPath) PreferenceFromCommunities(table map[uint32]int) (int, bool) {
+	if b.Communities == nil {
+		return 0, false
 	}
 
-	if c.BGPPathA.LocalPref > b.BGPPathA.LocalPref {
-		return -1
+	pref := 0
+	found := false
+	for _, com := range *b.Communities {
+		p, ok := table[com]
+		if !ok {
+			continue
+		}
+
+		if !found || p > pref {
+			pref = p
+			found = true
+		}
 	}
 
-	// 9.1.2.2.  Breaking Ties (Phase 2)
+	return pref, found
+}
 
-	// a)
-	if c.ASPathLen > b.ASPathLen {
-		return 1
-	}
+// compareCommunityPreference prefers the higher community preference. A path with a preference is
+// preferred over a path without one. 0 is returned if the preference does not break the tie.
+func (b *BGPPath) compareCommunityPreference(c *BGPPath, table map[uint32]int) int8 {
+	prefB, okB := b.PreferenceFromCommunities(table)
+	prefC, okC := c.PreferenceFromCommunities(table)
+
+	if okB != okC {
+		if okB {
+			return 1
+		}
 
-	if c.ASPathLen < b.ASPathLen {
 		return -1
 	}
 
-	// b)
-	if c.BGPPathA.Origin > b.BGPPathA.Origin {
+	if prefB > prefC {
 		return 1
 	}
 
-	if c.BGPPathA.Origin < b.BGPPathA.Origin {
+	if prefB < prefC {
 		return -1
 	}
 
-	// c)
-	if c.BGPPathA.MED > b.BGPPathA.MED {
+	return 0
+}
+
+// Usable checks if b may take part in path selection: The family of the next hop has to match the NLRI
+// and the next hop has to be reachable according to opts.NextHopResolver (RFC4271 9.1.2).
+// See Route.PathSelectionWithOptions.
+func (b *BGPPath) Usable(opts BGPSelectOptions) bool {
+	if !b.NextHopFamilyMatchesNLRI() {
+		return false
+	}
+
+	return b.NextHopReachable(opts.NextHopResolver)
+}
+
+// compareNextHopFamily ranks paths with a next hop not matching the family of the NLRI below all others
+// as they are unusable. Route.PathSelection excludes them entirely.
+func (b *BGPPath) compareNextHopFamily(c *BGPPath) int8 {
+	matchB := b.NextHopFamilyMatchesNLRI()
+	matchC := c.NextHopFamilyMatchesNLRI()
+
+	if matchB && !matchC {
 		return 1
 	}
 
-	if c.BGPPathA.MED < b.BGPPathA.MED {
+	if !matchB && matchC {
 		return -1
 	}
 
-	// d)
+	return 0
+}
+
+// BGPSelectOptions allows modifying the BGP best path selection. The options are applied by
+// Route.PathSelectionWithOptions, the LocRIB takes them from locRIB.NewWithOptions.
+type BGPSelectOptions struct {
+	// IBGPIgnoreRouterID stops the selection for two iBGP paths before the BGP identifier
+	// is compared, so equal cost iBGP paths are considered co-best (iBGP multipath)
+	IBGPIgnoreRouterID bool
+
+	// CommunityPreference maps communities to a preference which is compared before LOCAL_PREF.
+	// See PreferenceFromCommunities.
+	CommunityPreference map[uint32]int
+
+	// NextHopResolver is used to check the reachability of the next hop (RFC4271 9.1.2).
+	// Paths with an unreachable next hop are never preferred and excluded by
+	// Route.PathSelectionWithOptions. If nil all next hops are considered reachable. See NextHopReachable.
+	NextHopResolver func(bnet.IP) bool
+
+	// CompareOriginatorID always compares the ORIGINATOR_ID in place of the BGP identifier, a zero
+	// ORIGINATOR_ID is compared as is. By default a zero ORIGINATOR_ID falls back to the BGP identifier.
+	CompareOriginatorID bool
+
+	// ColorResolver maps the color of a path to the next hop of an SR policy. If set, paths whose
+	// color is mapped to a policy are preferred over paths without. See ColorNextHop.
+	ColorResolver func(color uint32) (*bnet.IP, bool)
+
+	// ASPathMultipathRelax only requires equal AS path lengths instead of identical AS paths
+	// for paths to be considered for ECMP. See ECMPWithOptions.
+	ASPathMultipathRelax bool
+
+	// CompareLinkBandwidth prefers the path with the higher link bandwidth after LOCAL_PREF.
+	// Paths without link bandwidth extended community are treated as having zero bandwidth. See LinkBandwidth.
+	CompareLinkBandwidth bool
+
+	// RouteServer stops the selection before the next hop is compared, so otherwise equal paths are
+	// considered co-best. On a route server the next hop is passed on unchanged and differs per client.
+	RouteServer bool
+}
+
+// RouteServerSelectOptions returns the default select options of a route server
+func RouteServerSelectOptions() BGPSelectOptions {
+	return BGPSelectOptions{
+		RouteServer: true,
+	}
+}
+
+// Select returns negative if b < c, 0 if paths are equal, positive if b > c
+func (b *BGPPath) Select(c *BGPPath) int8 {
+	if r := b.compareNextHopFamily(c); r != 0 {
+		return r
+	}
+
+	// Fast path: the vast majority of decisions is made by LOCAL_PREF
+	if b.BGPPathA.LocalPref != c.BGPPathA.LocalPref {
+		if b.BGPPathA.LocalPref > c.BGPPathA.LocalPref {
+			return 1
+		}
+
+		return -1
+	}
+
+	return b.breakTies(c, BGPSelectOptions{})
+}
+
+// SelectWithOptions works like Select but applies the modifications of the decision process set in opts
+func (b *BGPPath) SelectWithOptions(c *BGPPath, opts BGPSelectOptions) int8 {
+	if r := b.compareNextHopFamily(c); r != 0 {
+		return r
+	}
+
+	if opts.NextHopResolver != nil {
+		reachableB := b.NextHopReachable(opts.NextHopResolver)
+		reachableC := c.NextHopReachable(opts.NextHopResolver)
+
+		if reachableB && !reachableC {
+			return 1
+		}
+
+		if !reachableB && reachableC {
+			return -1
+		}
+	}
+
+	if opts.ColorResolver != nil {
+		_, coloredB := b.ColorNextHop(opts.ColorResolver)
+		_, coloredC := c.ColorNextHop(opts.ColorResolver)
+
+		if coloredB && !coloredC {
+			return 1
+		}
+
+		if !coloredB && coloredC {
+			return -1
+		}
+	}
+
+	if opts.CommunityPreference != nil {
+		if r := b.compareCommunityPreference(c, opts.CommunityPreference); r != 0 {
+			return r
+		}
+	}
+
+	if c.BGPPathA.LocalPref < b.BGPPathA.LocalPref {
+		return 1
+	}
+
+	if c.BGPPathA.LocalPref > b.BGPPathA.LocalPref {
+		return -1
+	}
+
+	if opts.CompareLinkBandwidth {
+		bwB, _ := b.LinkBandwidth()
+		bwC, _ := c.LinkBandwidth()
+
+		if bwB > bwC {
+			return 1
+		}
+
+		if bwB < bwC {
+			return -1
+		}
+	}
+
+	return b.breakTies(c, opts)
+}
+
+// breakTies compares paths with equal degree of preference
+func (b *BGPPath) breakTies(c *BGPPath, opts BGPSelectOptions) int8 {
+	// 9.1.2.2.  Breaking Ties (Phase 2)
+
+	// a)
+	if c.ASPathLen > b.ASPathLen {
+		return 1
+	}
+
+	if c.ASPathLen < b.ASPathLen {
+		return -1
+	}
+
+	// b)
+	if c.BGPPathA.Origin > b.BGPPathA.Origin {
+		return 1
+	}
+
+	if c.BGPPathA.Origin < b.BGPPathA.Origin {
+		return -1
+	}
+
+	// RFC7311 4.: AIGP is considered before MED, only if both paths carry it
+	if x := b.BGPPathA.compareAIGP(c.BGPPathA); x != 0 {
+		return x
+	}
+
+	// c)
+	if c.BGPPathA.MED > b.BGPPathA.MED {
+		return 1
+	}
+
+	if c.BGPPathA.MED < b.BGPPathA.MED {
+		return -1
+	}
+
+	// d)
 	if c.BGPPathA.EBGP && !b.BGPPathA.EBGP {
 		return -1
 	}
@@ -417,18 +1132,29 @@ func (b *BGPPath) Select(c *BGPPath) int8 {
 		return 1
 	}
 
-	// e) TODO: interior cost (hello IS-IS and OSPF)
+	// e) interior cost
+	if c.BGPPathA.IGPMetric > b.BGPPathA.IGPMetric {
+		return 1
+	}
+
+	if c.BGPPathA.IGPMetric < b.BGPPathA.IGPMetric {
+		return -1
+	}
+
+	if opts.IBGPIgnoreRouterID && !b.BGPPathA.EBGP && !c.BGPPathA.EBGP {
+		return 0
+	}
 
 	// f) + RFC4456 9. (Route Reflection)
 	bgpIdentifierC := c.BGPPathA.BGPIdentifier
 	bgpIdentifierB := b.BGPPathA.BGPIdentifier
 
 	// IF an OriginatorID (set by an RR) is present, use this instead of Originator
-	if c.BGPPathA.OriginatorID != 0 {
+	if c.BGPPathA.OriginatorID != 0 || opts.CompareOriginatorID {
 		bgpIdentifierC = c.BGPPathA.OriginatorID
 	}
 
-	if b.BGPPathA.OriginatorID != 0 {
+	if b.BGPPathA.OriginatorID != 0 || opts.CompareOriginatorID {
 		bgpIdentifierB = b.BGPPathA.OriginatorID
 	}
 
@@ -460,6 +1186,10 @@ func (b *BGPPath) Select(c *BGPPath) int8 {
 		return -1
 	}
 
+	if opts.RouteServer {
+		return 0
+	}
+
 	if c.BGPPathA.NextHop.Compare(b.BGPPathA.NextHop) == -1 {
 		return 1
 	}
@@ -468,6 +1198,15 @@ func (b *BGPPath) Select(c *BGPPath) int8 {
 		return -1
 	}
 
+	// Add-path: make the order of otherwise equal paths deterministic. The lower path identifier wins.
+	if b.PathIdentifier < c.PathIdentifier {
+		return 1
+	}
+
+	if b.PathIdentifier > c.PathIdentifier {
+		return -1
+	}
+
 	return 0
 }
 
@@ -496,6 +1235,10 @@ func (b *BGPPath) betterECMP(c *BGPPath) bool {
 		return true
 	}
 
+	if x := b.BGPPathA.compareAIGP(c.BGPPathA); x != 0 {
+		return x < 0
+	}
+
 	if c.BGPPathA.MED > b.BGPPathA.MED {
 		return false
 	}
@@ -504,6 +1247,14 @@ func (b *BGPPath) betterECMP(c *BGPPath) bool {
 		return true
 	}
 
+	if c.BGPPathA.IGPMetric > b.BGPPathA.IGPMetric {
+		return false
+	}
+
+	if c.BGPPathA.IGPMetric < b.BGPPathA.IGPMetric {
+		return true
+	}
+
 	return false
 }
 
@@ -564,6 +1315,7 @@ func (b *BGPPath) String() string {
 	if b.ClusterList != nil {
 		fmt.Fprintf(buf, ", ClusterList %s", b.ClusterListString())
 	}
+	fmt.Fprintf(buf, ", Source Protocol: %s", b.SourceProtocol)
 
 	return buf.String()
 }
@@ -602,6 +1354,10 @@ func (b *BGPPath) Print() string {
 		fmt.Fprintf(buf, "\t\tLargeCommunities: %v\n", *b.LargeCommunities)
 	}
 
+	if b.ExtendedCommunities != nil {
+		fmt.Fprintf(buf, "\t\tExtendedCommunities: %s\n", b.ExtendedCommunitiesString())
+	}
+
 	if b.BGPPathA.OriginatorID != 0 {
 		oid := convert.Uint32Byte(b.BGPPathA.OriginatorID)
 		fmt.Fprintf(buf, "\t\tOriginatorID: %d.%d.%d.%d\n", oid[0], oid[1], oid[2], oid[3])
@@ -609,16 +1365,316 @@ func (b *BGPPath) Print() string {
 	if b.ClusterList != nil {
 		fmt.Fprintf(buf, "\t\tClusterList %s\n", b.ClusterListString())
 	}
+	if !b.ReceivedAt.IsZero() {
+		fmt.Fprintf(buf, "\t\tAge: %s\n", b.AgeString())
+	}
 
 	return buf.String()
 }
 
-// Prepend the given BGPPath with the given ASN given times
+// Age gets the time since the path was received. It is 0 if ReceivedAt is not set.
+func (b *BGPPath) Age() time.Duration {
+	if b.ReceivedAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(b.ReceivedAt)
+}
+
+// AgeString renders the age of the path as common on router CLIs, e.g. "03:04:05", "2d03h" or "1w2d03h".
+// It is "never" if ReceivedAt is not set.
+func (b *BGPPath) AgeString() string {
+	if b.ReceivedAt.IsZero() {
+		return "never"
+	}
+
+	return formatAge(b.Age())
+}
+
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	const day = 24 * time.Hour
+	const week = 7 * day
+
+	if d < day {
+		return fmt.Sprintf("%02d:%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second))
+	}
+
+	hours := int(d % day / time.Hour)
+	if d < week {
+		return fmt.Sprintf("%dd%02dh", int(d/day), hours)
+	}
+
+	return fmt.Sprintf("%dw%dd%02dh", int(d/week), int(d%week/day), hours)
+}
+
+// ShowFormat renders the path in the format used by "show ip bgp" on common router CLIs
+func (b *BGPPath) ShowFormat(best bool) string {
+	// bio has no notion of weight so it's always 0
+	return fmt.Sprintf("%s %-19s %6d %6d %6d %s", bestMarker(best), b.BGPPathA.NextHop, b.BGPPathA.MED, b.BGPPathA.LocalPref, 0, b.pathWithOrigin())
+}
+
+// Summary renders the key decision fields of the path in one compact line (best marker, next hop, MED,
+// local pref, AS path and origin code). As a path does not know if it's the best one, the caller has to tell.
+func (b *BGPPath) Summary(best bool) string {
+	return fmt.Sprintf("%s %s %d %d %s", bestMarker(best), b.BGPPathA.NextHop, b.BGPPathA.MED, b.BGPPathA.LocalPref, b.pathWithOrigin())
+}
+
+func bestMarker(best bool) string {
+	if best {
+		return "*>"
+	}
+
+	return "* "
+}
+
+func (b *BGPPath) pathWithOrigin() string {
+	if b.ASPath != nil && len(*b.ASPath) > 0 {
+		return b.ASPath.String() + " " + b.originCode()
+	}
+
+	return b.originCode()
+}
+
+func (b *BGPPath) originCode() string {
+	switch b.BGPPathA.Origin {
+	case 0:
+		return "i"
+	case 1:
+		return "e"
+	default:
+		return "?"
+	}
+}
+
+// OriginAS gets the ASN the path originated from. The bool is false if the AS path is empty or ends
+// with an AS_SET, as the origin is ambiguous then.
+func (b *BGPPath) OriginAS() (uint32, bool) {
+	if b.ASPath == nil || len(*b.ASPath) == 0 {
+		return 0, false
+	}
+
+	last := (*b.ASPath)[len(*b.ASPath)-1]
+	if last.Type != types.ASSequence {
+		return 0, false
+	}
+
+	asn := last.GetLastASN()
+	if asn == nil {
+		return 0, false
+	}
+
+	return *asn, true
+}
+
+// NeighborAS gets the first ASN of the AS path, i.e. the AS the path was learned from. The bool is false
+// if the AS path is empty or starts with an AS_SET.
+func (b *BGPPath) NeighborAS() (uint32, bool) {
+	if b.ASPath == nil || len(*b.ASPath) == 0 {
+		return 0, false
+	}
+
+	first := (*b.ASPath)[0]
+	if first.Type != types.ASSequence || len(first.ASNs) == 0 {
+		return 0, false
+	}
+
+	return first.ASNs[0], true
+}
+
+// PenultimateAS gets the ASN just before the origin AS. Prepends of the origin AS are skipped. The bool is false
+// if there is no such ASN or if it or the origin are part of an AS_SET.
+func (b *BGPPath) PenultimateAS() (uint32, bool) {
+	origin, ok := b.OriginAS()
+	if !ok {
+		return 0, false
+	}
+
+	for i := len(*b.ASPath) - 1; i >= 0; i-- {
+		seg := (*b.ASPath)[i]
+		if seg.Type != types.ASSequence {
+			return 0, false
+		}
+
+		for j := len(seg.ASNs) - 1; j >= 0; j-- {
+			if seg.ASNs[j] != origin {
+				return seg.ASNs[j], true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// ContainsSequence checks if asns are traversed consecutively in the given order. AS_SETs are not considered.
+func (b *BGPPath) ContainsSequence(asns []uint32) bool {
+	if b.ASPath == nil || len(asns) == 0 {
+		return false
+	}
+
+	run := make([]uint32, 0, b.ASPathLen)
+	for _, seg := range *b.ASPath {
+		if seg.Type != types.ASSequence {
+			if containsSequence(run, asns) {
+				return true
+			}
+
+			run = run[:0]
+			continue
+		}
+
+		run = append(run, seg.ASNs...)
+	}
+
+	return containsSequence(run, asns)
+}
+
+func containsSequence(haystack []uint32, needle []uint32) bool {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		found := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequiresFourOctetASN checks if any ASN of the AS path does not fit into two octets
+func (b *BGPPath) RequiresFourOctetASN() bool {
+	if b.ASPath == nil {
+		return false
+	}
+
+	for _, seg := range *b.ASPath {
+		for _, asn := range seg.ASNs {
+			if asn > math.MaxUint16 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ContainsReservedASN returns the first reserved ASN of the AS path. Reserved are AS 0 (RFC7607),
+// AS_TRANS (RFC6793), documentation ASNs (RFC5398), 65535 (RFC7300) and 4294967295 (RFC7300).
+func (b *BGPPath) ContainsReservedASN() (uint32, bool) {
+	if b.ASPath == nil {
+		return 0, false
+	}
+
+	for _, seg := range *b.ASPath {
+		for _, asn := range seg.ASNs {
+			if isReservedASN(asn) {
+				return asn, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func isReservedASN(asn uint32) bool {
+	switch {
+	case asn == 0:
+		return true
+	case asn == 23456:
+		return true
+	case asn >= 64496 && asn <= 64511:
+		return true
+	case asn == 65535:
+		return true
+	case asn >= 65536 && asn <= 65551:
+		return true
+	case asn == math.MaxUint32:
+		return true
+	}
+
+	return false
+}
+
+// ASPathAbbreviated renders the AS path keeping only the first head and the last tail ASNs.
+// Omitted ASNs are replaced by an ellipsis, e.g. "1 2 … 99 100".
+func (b *BGPPath) ASPathAbbreviated(head, tail int) string {
+	if b.ASPath == nil {
+		return ""
+	}
+
+	asns := make([]uint32, 0, b.ASPathLen)
+	for _, seg := range *b.ASPath {
+		asns = append(asns, seg.ASNs...)
+	}
+
+	if head < 0 {
+		head = 0
+	}
+
+	if tail < 0 {
+		tail = 0
+	}
+
+	parts := make([]string, 0, head+tail+1)
+	if head+tail >= len(asns) {
+		for _, asn := range asns {
+			parts = append(parts, fmt.Sprintf("%d", asn))
+		}
+
+		return strings.Join(parts, " ")
+	}
+
+	for _, asn := range asns[:head] {
+		parts = append(parts, fmt.Sprintf("%d", asn))
+	}
+
+	parts = append(parts, "…")
+	for _, asn := range asns[len(asns)-tail:] {
+		parts = append(parts, fmt.Sprintf("%d", asn))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// MaxASPathASNs is the maximum number of ASNs Prepend grows an AS path to. Longer AS paths would not fit
+// into a BGP UPDATE message of at most 4096 bytes (RFC4271 4.) anyway.
+const MaxASPathASNs = 1000
+
+// Prepend the given BGPPath with the given ASN given times. The AS path is never grown beyond MaxASPathASNs
+// ASNs, surplus prepends are dropped.
 func (b *BGPPath) Prepend(asn uint32, times uint16) {
 	if times == 0 {
 		return
 	}
 
+	n := 0
+	for _, seg := range *b.ASPath {
+		n += len(seg.ASNs)
+	}
+
+	if n+int(times) > MaxASPathASNs {
+		remaining := 0
+		if n < MaxASPathASNs {
+			remaining = MaxASPathASNs - n
+		}
+
+		log.Warningf("Prepending AS%d %d times exceeds the AS path limit of %d ASNs. Prepending %d times.", asn, times, MaxASPathASNs, remaining)
+		if remaining == 0 {
+			return
+		}
+
+		times = uint16(remaining)
+	}
+
 	if len(*b.ASPath) == 0 {
 		b.insertNewASSequence()
 	}
@@ -629,7 +1685,7 @@ func (b *BGPPath) Prepend(asn uint32, times uint16) {
 	}
 
 	for i := 0; i < int(times); i++ {
-		if len(*b.ASPath) == types.MaxASNsSegment {
+		if len((*b.ASPath)[0].ASNs) >= types.MaxASNsSegment {
 			b.insertNewASSequence()
 		}
 
@@ -643,17 +1699,85 @@ func (b *BGPPath) Prepend(asn uint32, times uint16) {
 	b.ASPathLen = b.ASPath.Length()
 }
 
+// StripConfederationSegments removes all AS_CONFED_SEQUENCE and AS_CONFED_SET segments from the AS path,
+// as required when a path leaves the confederation (RFC5065 5.1)
+func (b *BGPPath) StripConfederationSegments() {
+	if b.ASPath == nil {
+		return
+	}
+
+	asPath := make(types.ASPath, 0, len(*b.ASPath))
+	for _, seg := range *b.ASPath {
+		if seg.IsConfed() {
+			continue
+		}
+
+		asPath = append(asPath, seg)
+	}
+
+	b.ASPath = &asPath
+	b.ASPathLen = b.ASPath.Length()
+}
+
+// ReplaceASN replaces all occurrences of from in the AS path with to (as-override)
+func (b *BGPPath) ReplaceASN(from, to uint32) {
+	if b.ASPath == nil {
+		return
+	}
+
+	for i := range *b.ASPath {
+		seg := &(*b.ASPath)[i]
+		asns := make([]uint32, len(seg.ASNs))
+		for j, asn := range seg.ASNs {
+			if asn == from {
+				asn = to
+			}
+
+			asns[j] = asn
+		}
+
+		seg.ASNs = asns
+	}
+
+	b.ASPathLen = b.ASPath.Length()
+}
+
 func (b *BGPPath) insertNewASSequence() {
+	b.insertNewSegment(types.ASSequence)
+}
+
+func (b *BGPPath) insertNewSegment(segType uint8) {
 	pa := make(types.ASPath, len(*b.ASPath)+1)
 	copy(pa[1:], (*b.ASPath))
 	pa[0] = types.ASPathSegment{
 		ASNs: make([]uint32, 0),
-		Type: types.ASSequence,
+		Type: segType,
 	}
 
 	b.ASPath = &pa
 }
 
+// PrependConfedASN prepends subASN to the leading AS_CONFED_SEQUENCE which is created if necessary.
+// This is done when a path is advertised to a peer in another member AS of the confederation (RFC5065 5.3).
+// The AS path length used for path selection is not affected.
+func (b *BGPPath) PrependConfedASN(subASN uint32) {
+	if b.ASPath == nil {
+		b.ASPath = &types.ASPath{}
+	}
+
+	if len(*b.ASPath) == 0 || (*b.ASPath)[0].Type != types.ASConfedSequence || len((*b.ASPath)[0].ASNs) >= types.MaxASNsSegment {
+		b.insertNewSegment(types.ASConfedSequence)
+	}
+
+	old := (*b.ASPath)[0].ASNs
+	asns := make([]uint32, len(old)+1)
+	copy(asns[1:], old)
+	asns[0] = subASN
+	(*b.ASPath)[0].ASNs = asns
+
+	b.ASPathLen = b.ASPath.Length()
+}
+
 // Copy creates a deep copy of a BGPPath
 func (b *BGPPath) Copy() *BGPPath {
 	if b == nil {
@@ -680,55 +1804,187 @@ func (b *BGPPath) Copy() *BGPPath {
 		copy(*cp.LargeCommunities, *b.LargeCommunities)
 	}
 
+	if b.ExtendedCommunities != nil {
+		extCommunities := make([]types.ExtendedCommunity, len(*b.ExtendedCommunities))
+		copy(extCommunities, *b.ExtendedCommunities)
+		cp.ExtendedCommunities = &extCommunities
+	}
+
 	if b.ClusterList != nil {
 		clusterList := make(types.ClusterList, len(*cp.ClusterList))
 		cp.ClusterList = &clusterList
 		copy(*cp.ClusterList, *b.ClusterList)
 	}
 
+	cp.PMSITunnel = b.PMSITunnel.Copy()
+	cp.BGPLSAttribute = b.BGPLSAttribute.Copy()
+
 	return &cp
 }
 
+// ASPathEditDistance computes the Levenshtein distance between the ASNs of the AS paths of b and other.
+// The ASNs of all segments are considered in order of their appearance.
+func (b *BGPPath) ASPathEditDistance(other *BGPPath) int {
+	x := b.flatASPath()
+	y := other.flatASPath()
+
+	prev := make([]int, len(y)+1)
+	cur := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		cur[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(y)]
+}
+
+func (b *BGPPath) flatASPath() []uint32 {
+	ret := make([]uint32, 0)
+	if b.ASPath == nil {
+		return ret
+	}
+
+	for _, seg := range *b.ASPath {
+		ret = append(ret, seg.ASNs...)
+	}
+
+	return ret
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// ASPathFingerprint computes a hash over the segment types and ASNs of the AS path only
+func (b *BGPPath) ASPathFingerprint() uint64 {
+	h := fnv.New64a()
+	if b.ASPath == nil {
+		return h.Sum64()
+	}
+
+	buf := make([]byte, 4)
+	for _, seg := range *b.ASPath {
+		h.Write([]byte{seg.Type})
+		binary.BigEndian.PutUint32(buf, uint32(len(seg.ASNs)))
+		h.Write(buf)
+
+		for _, asn := range seg.ASNs {
+			binary.BigEndian.PutUint32(buf, asn)
+			h.Write(buf)
+		}
+	}
+
+	return h.Sum64()
+}
+
 // ComputeHash computes an hash over all attributes of the path
 func (b *BGPPath) ComputeHash() string {
-	s := fmt.Sprintf("%s\t%d\t%s\t%d\t%d\t%v\t%d\t%s\t%s\t%s\t%d\t%s",
-		b.BGPPathA.NextHop.String(),
+	s := fmt.Sprintf("%s\t%d\t%s\t%d\t%d\t%v\t%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%d\t%v\t%s",
+		ipHashString(b.BGPPathA.NextHop),
 		b.BGPPathA.LocalPref,
-		b.ASPath.String(),
+		b.ASPath.HashString(),
 		b.BGPPathA.Origin,
 		b.BGPPathA.MED,
 		b.BGPPathA.EBGP,
 		b.BGPPathA.BGPIdentifier,
-		b.BGPPathA.Source.String(),
+		ipHashString(b.BGPPathA.Source),
 		b.Communities.String(),
 		b.LargeCommunities.String(),
+		b.ExtendedCommunitiesString(),
 		b.BGPPathA.OriginatorID,
-		b.ClusterList.String())
+		b.ClusterList.String(),
+		b.PMSITunnel.String(),
+		b.BGPLSAttribute.String(),
+		b.BGPPathA.AIGP,
+		b.BGPPathA.AIGPPresent,
+		b.BGPPathA.hashString())
 
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
 }
 
+// hashString formats the attributes of b considered by compare but not covered by the format of ComputeHash
+func (b *BGPPathA) hashString() string {
+	aggr := ""
+	if b.Aggregator != nil {
+		aggr = fmt.Sprintf("%d:%d", b.Aggregator.ASN, b.Aggregator.Address)
+	}
+
+	return fmt.Sprintf("%s\t%v\t%v\t%s",
+		ipHashString(b.NextHopLinkLocal),
+		b.MEDPresent(),
+		b.AtomicAggregate,
+		aggr)
+}
+
+// ipHashString formats addr for ComputeHash. bnet.IP.String is not nil safe.
+func ipHashString(addr *bnet.IP) string {
+	if addr == nil {
+		return ""
+	}
+
+	return addr.String()
+}
+
+// PathIdentifierBytes gets the path identifier in network byte order as used in add-path NLRIs (RFC7911)
+func (b *BGPPath) PathIdentifierBytes() [4]byte {
+	ret := [4]byte{}
+	binary.BigEndian.PutUint32(ret[:], b.PathIdentifier)
+	return ret
+}
+
+// PathIdentifierFromBytes parses a path identifier in network byte order
+func PathIdentifierFromBytes(b []byte) (uint32, error) {
+	if len(b) != 4 {
+		return 0, fmt.Errorf("invalid path identifier length: %d", len(b))
+	}
+
+	return binary.BigEndian.Uint32(b), nil
+}
+
 // ComputeHash computes an hash over all attributes of the path
 func (b *BGPPath) ComputeHashWithPathID() string {
-	s := fmt.Sprintf("%s\t%d\t%s\t%d\t%d\t%v\t%d\t%s\t%s\t%s\t%d\t%d\t%s",
-		b.BGPPathA.NextHop.String(),
+	s := fmt.Sprintf("%s\t%d\t%s\t%d\t%d\t%v\t%d\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%d\t%v\t%s",
+		ipHashString(b.BGPPathA.NextHop),
 		b.BGPPathA.LocalPref,
-		b.ASPath.String(),
+		b.ASPath.HashString(),
 		b.BGPPathA.Origin,
 		b.BGPPathA.MED,
 		b.BGPPathA.EBGP,
 		b.BGPPathA.BGPIdentifier,
-		b.BGPPathA.Source.String(),
+		ipHashString(b.BGPPathA.Source),
 		b.Communities.String(),
 		b.LargeCommunities.String(),
+		b.ExtendedCommunitiesString(),
 		b.PathIdentifier,
 		b.BGPPathA.OriginatorID,
-		b.ClusterList.String())
+		b.ClusterList.String(),
+		b.PMSITunnel.String(),
+		b.BGPLSAttribute.String(),
+		b.BGPPathA.AIGP,
+		b.BGPPathA.AIGPPresent,
+		b.BGPPathA.hashString())
 
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))
 }
 
-// CommunitiesString returns the formated communities
+// CommunitiesString returns the formated communities. BLACKHOLE (RFC7999) is rendered symbolically.
 func (b *BGPPath) CommunitiesString() string {
 	str := &strings.Builder{}
 
@@ -736,12 +1992,165 @@ func (b *BGPPath) CommunitiesString() string {
 		if i > 0 {
 			str.WriteByte(' ')
 		}
+
+		if com == types.WellKnownCommunityBlackhole {
+			str.WriteString("BLACKHOLE")
+			continue
+		}
+
 		str.WriteString(types.CommunityStringForUint32(com))
 	}
 
 	return str.String()
 }
 
+// HasGracefulShutdown checks if the path carries the GRACEFUL_SHUTDOWN community (RFC8326)
+func (b *BGPPath) HasGracefulShutdown() bool {
+	if b.Communities == nil {
+		return false
+	}
+
+	for _, com := range *b.Communities {
+		if com == types.WellKnownCommunityGracefulShutdown {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasBlackholeCommunity checks if the path carries the BLACKHOLE community (RFC7999)
+func (b *BGPPath) HasBlackholeCommunity() bool {
+	if b.Communities == nil {
+		return false
+	}
+
+	for _, com := range *b.Communities {
+		if com == types.WellKnownCommunityBlackhole {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConflictingCommunities returns each of the conflict groups all communities of which are carried by the path
+func (b *BGPPath) ConflictingCommunities(conflicts [][]uint32) [][]uint32 {
+	ret := make([][]uint32, 0)
+	if b.Communities == nil {
+		return ret
+	}
+
+	present := make(map[uint32]struct{}, len(*b.Communities))
+	for _, com := range *b.Communities {
+		present[com] = struct{}{}
+	}
+
+	for _, group := range conflicts {
+		if len(group) == 0 {
+			continue
+		}
+
+		complete := true
+		for _, com := range group {
+			if _, ok := present[com]; !ok {
+				complete = false
+				break
+			}
+		}
+
+		if complete {
+			ret = append(ret, group)
+		}
+	}
+
+	return ret
+}
+
+// MergeCommunitiesFrom appends the communities (and large communities if includeLarge is set) of other to b.
+// The result is deduplicated keeping the first occurrence of each community. New slices are allocated,
+// so slices shared with other paths are not modified.
+func (b *BGPPath) MergeCommunitiesFrom(other *BGPPath, includeLarge bool) {
+	if b.Communities != nil || other.Communities != nil {
+		coms := make(types.Communities, 0)
+		seen := make(map[uint32]struct{})
+		for _, x := range []*types.Communities{b.Communities, other.Communities} {
+			if x == nil {
+				continue
+			}
+
+			for _, com := range *x {
+				if _, ok := seen[com]; ok {
+					continue
+				}
+
+				seen[com] = struct{}{}
+				coms = append(coms, com)
+			}
+		}
+
+		b.Communities = &coms
+	}
+
+	if !includeLarge || (b.LargeCommunities == nil && other.LargeCommunities == nil) {
+		return
+	}
+
+	lcoms := make(types.LargeCommunities, 0)
+	seen := make(map[types.LargeCommunity]struct{})
+	for _, x := range []*types.LargeCommunities{b.LargeCommunities, other.LargeCommunities} {
+		if x == nil {
+			continue
+		}
+
+		for _, com := range *x {
+			if _, ok := seen[com]; ok {
+				continue
+			}
+
+			seen[com] = struct{}{}
+			lcoms = append(lcoms, com)
+		}
+	}
+
+	b.LargeCommunities = &lcoms
+}
+
+// SuggestLargeEquivalent maps each community x:y of the path to the large community asn:x:y.
+// The path is not modified.
+func (b *BGPPath) SuggestLargeEquivalent(asn uint32) []types.LargeCommunity {
+	if b.Communities == nil {
+		return []types.LargeCommunity{}
+	}
+
+	ret := make([]types.LargeCommunity, len(*b.Communities))
+	for i, com := range *b.Communities {
+		ret[i] = types.LargeCommunity{
+			GlobalAdministrator: asn,
+			DataPart1:           com >> 16,
+			DataPart2:           com & 0xFFFF,
+		}
+	}
+
+	return ret
+}
+
+// SplitCommunitiesForWire splits the communities into chunks each fitting into a single COMMUNITIES attribute
+func (b *BGPPath) SplitCommunitiesForWire() [][]uint32 {
+	if b.Communities == nil || len(*b.Communities) == 0 {
+		return nil
+	}
+
+	coms := *b.Communities
+	ret := make([][]uint32, 0, len(coms)/types.MaxCommunitiesPerAttribute+1)
+	for len(coms) > types.MaxCommunitiesPerAttribute {
+		ret = append(ret, coms[:types.MaxCommunitiesPerAttribute])
+		coms = coms[types.MaxCommunitiesPerAttribute:]
+	}
+
+	return append(ret, coms)
+}
+
 // ClusterListString returns the formated ClusterList
 func (b *BGPPath) ClusterListString() string {
 	str := &strings.Builder{}
@@ -758,6 +2167,15 @@ func (b *BGPPath) ClusterListString() string {
 	return str.String()
 }
 
+// ExtendedCommunitiesString returns the formated extended communities
+func (b *BGPPath) ExtendedCommunitiesString() string {
+	if b.ExtendedCommunities == nil {
+		return ""
+	}
+
+	return types.ExtendedCommunitiesString(*b.ExtendedCommunities)
+}
+
 // LargeCommunitiesString returns the formated communities
 func (b *BGPPath) LargeCommunitiesString() string {
 	str := &strings.Builder{}
@@ -771,3 +2189,12 @@ func (b *BGPPath) LargeCommunitiesString() string {
 
 	return str.String()
 }
+
+// AddressScope gets the scope of the next hop, e.g. to reject paths with bogon next hops
+func (b *BGPPath) AddressScope() bnet.Scope {
+	if b.BGPPathA.NextHop == nil {
+		return bnet.ScopeGlobal
+	}
+
+	return b.BGPPathA.NextHop.Scope()
+}
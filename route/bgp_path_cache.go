@@ -1,15 +1,24 @@
 package route
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/bio-routing/tflow2/convert"
+)
 
 const initialBGPPathACacheSize = 100000
 
 var (
-	bgpC *bgpPathACache
+	bgpC   *bgpPathACache
+	pathIC *bgpPathInternCache
+	clC    *clusterListCache
 )
 
 func init() {
 	bgpC = newBGPPathACache()
+	pathIC = newBGPPathInternCache()
+	clC = newClusterListCache()
 }
 
 type bgpPathACache struct {
@@ -35,3 +44,211 @@ func (bgpc *bgpPathACache) get(p *BGPPathA) *BGPPathA {
 	bgpc.cacheMu.Unlock()
 	return p
 }
+
+// DedupCacheSnapshot is a copy of the BGPPathA dedup cache
+type DedupCacheSnapshot struct {
+	cache map[BGPPathA]*BGPPathA
+}
+
+// ResetDedupCache drops all entries of the BGPPathA dedup cache
+func ResetDedupCache() {
+	bgpC.cacheMu.Lock()
+	defer bgpC.cacheMu.Unlock()
+
+	bgpC.cache = make(map[BGPPathA]*BGPPathA, initialBGPPathACacheSize)
+}
+
+// SnapshotDedupCache gets a copy of the current BGPPathA dedup cache
+func SnapshotDedupCache() *DedupCacheSnapshot {
+	bgpC.cacheMu.Lock()
+	defer bgpC.cacheMu.Unlock()
+
+	s := &DedupCacheSnapshot{
+		cache: make(map[BGPPathA]*BGPPathA, len(bgpC.cache)),
+	}
+
+	for k, v := range bgpC.cache {
+		s.cache[k] = v
+	}
+
+	return s
+}
+
+// RestoreDedupCache replaces the BGPPathA dedup cache with the contents of s
+func RestoreDedupCache(s *DedupCacheSnapshot) {
+	cache := make(map[BGPPathA]*BGPPathA, len(s.cache))
+	for k, v := range s.cache {
+		cache[k] = v
+	}
+
+	bgpC.cacheMu.Lock()
+	defer bgpC.cacheMu.Unlock()
+
+	bgpC.cache = cache
+}
+
+type bgpPathInternCache struct {
+	cache   map[string][]*internedPath
+	cacheMu sync.Mutex
+}
+
+type internedPath struct {
+	path *BGPPath
+	refs uint64
+}
+
+func newBGPPathInternCache() *bgpPathInternCache {
+	return &bgpPathInternCache{
+		cache: make(map[string][]*internedPath),
+	}
+}
+
+// InternPath returns a shared instance of a path equal to p in all fields (including the path identifier).
+// If no such path is known yet, p itself is interned. Interned paths are shared by all users and thus must be
+// treated as immutable: Any modification has to be done on a Copy().
+// Interned paths are reference counted. Every call to InternPath has to be matched by a call to ReleasePath
+// once the caller no longer uses the path, otherwise the path is never evicted from the cache.
+func InternPath(p *BGPPath) *BGPPath {
+	return pathIC.get(p)
+}
+
+// ReleasePath drops a reference to the interned path p. The path is evicted from the cache once the
+// last reference was released. Releasing a path that is not interned is a no-op.
+func ReleasePath(p *BGPPath) {
+	pathIC.release(p)
+}
+
+func (c *bgpPathInternCache) get(p *BGPPath) *BGPPath {
+	h := p.ComputeHashWithPathID()
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	for _, x := range c.cache[h] {
+		if x.path.internEqual(p) {
+			x.refs++
+			return x.path
+		}
+	}
+
+	p.Dedup()
+	c.cache[h] = append(c.cache[h], &internedPath{
+		path: p,
+		refs: 1,
+	})
+	return p
+}
+
+func (c *bgpPathInternCache) release(p *BGPPath) {
+	h := p.ComputeHashWithPathID()
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	bucket := c.cache[h]
+	for i, x := range bucket {
+		if x.path != p {
+			continue
+		}
+
+		x.refs--
+		if x.refs > 0 {
+			return
+		}
+
+		bucket = append(bucket[:i], bucket[i+1:]...)
+		if len(bucket) == 0 {
+			delete(c.cache, h)
+			return
+		}
+
+		c.cache[h] = bucket
+		return
+	}
+}
+
+func (c *bgpPathInternCache) len() int {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	n := 0
+	for _, bucket := range c.cache {
+		n += len(bucket)
+	}
+
+	return n
+}
+
+// internEqual checks if b and c are equal in every field, including those not considered by Compare
+func (b *BGPPath) internEqual(c *BGPPath) bool {
+	if !b.Compare(c) {
+		return false
+	}
+
+	if b.BGPPathA.HasMED != c.BGPPathA.HasMED || b.BGPPathA.IGPMetric != c.BGPPathA.IGPMetric {
+		return false
+	}
+
+	if b.ASPathLen != c.ASPathLen || b.AFI != c.AFI || b.SAFI != c.SAFI {
+		return false
+	}
+
+	if b.SourceProtocol != c.SourceProtocol || b.CommunitiesSetBy != c.CommunitiesSetBy || !b.ReceivedAt.Equal(c.ReceivedAt) {
+		return false
+	}
+
+	if len(b.UnknownAttributes) != len(c.UnknownAttributes) {
+		return false
+	}
+
+	for i := range b.UnknownAttributes {
+		if !b.UnknownAttributes[i].Compare(&c.UnknownAttributes[i]) {
+			return false
+		}
+	}
+
+	if b.ResolvedNextHop == nil || c.ResolvedNextHop == nil {
+		return b.ResolvedNextHop == c.ResolvedNextHop
+	}
+
+	return b.ResolvedNextHop.Compare(c.ResolvedNextHop) == 0
+}
+
+// clusterListCache interns cluster lists. Reflected routes often carry identical cluster lists.
+type clusterListCache struct {
+	cache   map[string]*types.ClusterList
+	cacheMu sync.Mutex
+}
+
+func newClusterListCache() *clusterListCache {
+	return &clusterListCache{
+		cache: make(map[string]*types.ClusterList),
+	}
+}
+
+// internClusterList returns a shared instance of a cluster list equal to cl. Interned cluster lists
+// must not be modified. Copy() allocates a new cluster list, so copies can be modified safely.
+func internClusterList(cl *types.ClusterList) *types.ClusterList {
+	if cl == nil || len(*cl) == 0 {
+		return cl
+	}
+
+	return clC.get(cl)
+}
+
+func (c *clusterListCache) get(cl *types.ClusterList) *types.ClusterList {
+	key := make([]byte, 0, 4*len(*cl))
+	for _, cid := range *cl {
+		key = append(key, convert.Uint32Byte(cid)...)
+	}
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	if x, ok := c.cache[string(key)]; ok {
+		return x
+	}
+
+	c.cache[string(key)] = cl
+	return cl
+}
@@ -0,0 +1,186 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetDedupCache(t *testing.T) {
+	s := SnapshotDedupCache()
+	defer RestoreDedupCache(s)
+
+	a := (&BGPPathA{LocalPref: 4711}).Dedup()
+	assert.True(t, a == (&BGPPathA{LocalPref: 4711}).Dedup())
+
+	ResetDedupCache()
+
+	b := (&BGPPathA{LocalPref: 4711}).Dedup()
+	assert.True(t, a != b)
+	assert.Equal(t, *a, *b)
+}
+
+func TestSnapshotRestoreDedupCache(t *testing.T) {
+	s := SnapshotDedupCache()
+	defer RestoreDedupCache(s)
+
+	a := (&BGPPathA{LocalPref: 4712}).Dedup()
+	snap := SnapshotDedupCache()
+
+	ResetDedupCache()
+	assert.True(t, a != (&BGPPathA{LocalPref: 4712}).Dedup())
+
+	RestoreDedupCache(snap)
+	assert.True(t, a == (&BGPPathA{LocalPref: 4712}).Dedup())
+}
+
+func TestInternPath(t *testing.T) {
+	newPath := func(pathID uint32, coms ...uint32) *BGPPath {
+		c := types.Communities(coms)
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				Source:    bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{65001},
+				},
+			},
+			ASPathLen:      1,
+			Communities:    &c,
+			PathIdentifier: pathID,
+		}
+	}
+
+	a := InternPath(newPath(1, 100, 200))
+	assert.True(t, a == InternPath(newPath(1, 100, 200)))
+	assert.True(t, a != InternPath(newPath(2, 100, 200)))
+	assert.True(t, a != InternPath(newPath(1, 100)))
+
+	withMetric := newPath(1, 100, 200)
+	withMetric.BGPPathA.IGPMetric = 10
+	assert.True(t, a.Compare(withMetric))
+	assert.False(t, a.internEqual(withMetric))
+	assert.Equal(t, uint32(10), InternPath(withMetric).BGPPathA.IGPMetric)
+
+	tests := []struct {
+		name   string
+		modify func(p *BGPPath)
+	}{
+		{
+			name: "ReceivedAt",
+			modify: func(p *BGPPath) {
+				p.ReceivedAt = time.Unix(1600000000, 0)
+			},
+		},
+		{
+			name: "CommunitiesSetBy",
+			modify: func(p *BGPPath) {
+				p.CommunitiesSetBy = "policy/term"
+			},
+		},
+		{
+			name: "Address family",
+			modify: func(p *BGPPath) {
+				p.AFI = afiIPv4
+			},
+		},
+		{
+			name: "MED presence",
+			modify: func(p *BGPPath) {
+				p.BGPPathA.HasMED = true
+			},
+		},
+		{
+			name: "Unknown attributes",
+			modify: func(p *BGPPath) {
+				p.UnknownAttributes = []types.UnknownPathAttribute{
+					{
+						Optional: true,
+						TypeCode: 250,
+						Value:    []byte{1},
+					},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p := newPath(1, 100, 200)
+		test.modify(p)
+		assert.True(t, a != InternPath(p), test.name)
+	}
+}
+
+func TestReleasePath(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 4713,
+				Source:    bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	n := pathIC.len()
+
+	a := InternPath(newPath())
+	assert.True(t, a == InternPath(newPath()))
+	assert.Equal(t, n+1, pathIC.len())
+
+	ReleasePath(a)
+	assert.Equal(t, n+1, pathIC.len())
+	assert.True(t, a == InternPath(newPath()))
+
+	ReleasePath(a)
+	ReleasePath(a)
+	assert.Equal(t, n, pathIC.len())
+
+	// Releasing a path that is not interned (anymore) must be a no-op
+	ReleasePath(a)
+	ReleasePath(newPath())
+	assert.Equal(t, n, pathIC.len())
+
+	b := InternPath(newPath())
+	assert.True(t, a != b)
+	ReleasePath(b)
+	assert.Equal(t, n, pathIC.len())
+}
+
+func TestInternClusterList(t *testing.T) {
+	newPath := func(cids ...uint32) *BGPPath {
+		cl := types.ClusterList(cids)
+		return &BGPPath{
+			BGPPathA:    &BGPPathA{},
+			ClusterList: &cl,
+		}
+	}
+
+	a := newPath(1, 2, 3).Dedup()
+	b := newPath(1, 2, 3).Dedup()
+	c := newPath(1, 2).Dedup()
+
+	assert.True(t, a.ClusterList == b.ClusterList)
+	assert.True(t, &(*a.ClusterList)[0] == &(*b.ClusterList)[0])
+	assert.True(t, a.ClusterList != c.ClusterList)
+
+	// Copies must not share the interned cluster list
+	cp := a.Copy()
+	(*cp.ClusterList)[0] = 100
+	assert.Equal(t, types.ClusterList{1, 2, 3}, *b.ClusterList)
+
+	p := newPath(4, 5)
+	p.BGPPathA = NewBGPPathA()
+	p.ASPath = &types.ASPath{}
+	pb := p.ToProto()
+	assert.True(t, BGPPathFromProtoBGPPath(pb, true).ClusterList == BGPPathFromProtoBGPPath(pb, true).ClusterList)
+	assert.True(t, BGPPathFromProtoBGPPath(pb, false).ClusterList != BGPPathFromProtoBGPPath(pb, false).ClusterList)
+}
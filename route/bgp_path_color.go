@@ -0,0 +1,72 @@
+package route
+
+import (
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/bio-routing/tflow2/convert"
+)
+
+const (
+	// Color extended community (RFC9012 4.3)
+	extCommunityTypeOpaque    = 0x03
+	extCommunitySubTypeColor  = 0x0b
+	extCommunityColorValueIdx = 2
+)
+
+// Color gets the value of the first Color extended community (RFC9012) of the path.
+func (b *BGPPath) Color() (uint32, bool) {
+	com, ok := b.findExtCommunity(extCommunityTypeOpaque, extCommunitySubTypeColor)
+	if !ok {
+		return 0, false
+	}
+
+	return convert.Uint32b(com.Value[extCommunityColorValueIdx:]), true
+}
+
+// findExtCommunity gets the first extended community of the given type and sub type.
+func (b *BGPPath) findExtCommunity(typ uint8, subType uint8) (types.ExtendedCommunity, bool) {
+	for _, com := range b.extCommunities() {
+		if com.Type == typ && com.SubType == subType {
+			return com, true
+		}
+	}
+
+	return types.ExtendedCommunity{}, false
+}
+
+// extCommunities gets all extended communities of the path. Extended communities carried as unknown
+// attribute (e.g. by paths not decoded by bio) are considered, too.
+func (b *BGPPath) extCommunities() []types.ExtendedCommunity {
+	ret := make([]types.ExtendedCommunity, 0)
+	if b.ExtendedCommunities != nil {
+		ret = append(ret, *b.ExtendedCommunities...)
+	}
+
+	for _, attr := range b.UnknownAttributes {
+		if attr.TypeCode != types.ExtendedCommunitiesAttr {
+			continue
+		}
+
+		for i := 0; i+types.ExtendedCommunityLen <= len(attr.Value); i += types.ExtendedCommunityLen {
+			com, _ := types.ExtendedCommunityFromBytes(attr.Value[i : i+types.ExtendedCommunityLen])
+			ret = append(ret, com)
+		}
+	}
+
+	return ret
+}
+
+// ColorNextHop gets the next hop of the SR policy (tunnel) the color of the path is mapped to by resolver
+func (b *BGPPath) ColorNextHop(resolver func(color uint32) (*bnet.IP, bool)) (*bnet.IP, bool) {
+	color, ok := b.Color()
+	if !ok {
+		return nil, false
+	}
+
+	nh, ok := resolver(color)
+	if !ok || nh == nil {
+		return nil, false
+	}
+
+	return nh, true
+}
@@ -0,0 +1,86 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func colorPath(value []byte) *BGPPath {
+	return &BGPPath{
+		BGPPathA: &BGPPathA{
+			Source:  bnet.IPv4(0).Ptr(),
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+		UnknownAttributes: []types.UnknownPathAttribute{
+			{
+				Optional:   true,
+				Transitive: true,
+				TypeCode:   16,
+				Value:      value,
+			},
+		},
+	}
+}
+
+func TestColor(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          *BGPPath
+		expected      uint32
+		expectedFound bool
+	}{
+		{
+			name: "No extended communities",
+			path: &BGPPath{},
+		},
+		{
+			name: "Color after route target",
+			path: colorPath([]byte{
+				0x00, 0x02, 0xfd, 0xe8, 0, 0, 0, 100, // Route Target 65000:100
+				0x03, 0x0b, 0, 0, 0, 0, 0x01, 0x00, // Color 256
+			}),
+			expected:      256,
+			expectedFound: true,
+		},
+		{
+			name: "No color",
+			path: colorPath([]byte{
+				0x00, 0x02, 0xfd, 0xe8, 0, 0, 0, 100, // Route Target 65000:100
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		color, found := test.path.Color()
+		assert.Equal(t, test.expected, color, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+	}
+}
+
+func TestSelectColor(t *testing.T) {
+	opts := BGPSelectOptions{
+		ColorResolver: func(color uint32) (*bnet.IP, bool) {
+			if color == 100 {
+				return bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), true
+			}
+
+			return nil, false
+		},
+	}
+
+	colored := colorPath([]byte{0x03, 0x0b, 0, 0, 0, 0, 0, 100})
+	colored.BGPPathA.LocalPref = 100
+
+	unknownColor := colorPath([]byte{0x03, 0x0b, 0, 0, 0, 0, 0, 200})
+	unknownColor.BGPPathA.LocalPref = 200
+
+	nh, ok := colored.ColorNextHop(opts.ColorResolver)
+	assert.True(t, ok)
+	assert.Equal(t, bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), nh)
+
+	assert.Equal(t, int8(1), colored.SelectWithOptions(unknownColor, opts))
+	assert.Equal(t, int8(-1), colored.Select(unknownColor))
+}
@@ -0,0 +1,60 @@
+package route
+
+import (
+	"fmt"
+)
+
+// AttributeDiff describes a path attribute differing between two paths
+type AttributeDiff struct {
+	Attribute string
+	Old       string
+	New       string
+}
+
+// Diff returns the attributes of other differing from b. Old values are taken from b, new values from other.
+func (b *BGPPath) Diff(other *BGPPath) []AttributeDiff {
+	ret := make([]AttributeDiff, 0)
+	add := func(attr, old, new string) {
+		if old != new {
+			ret = append(ret, AttributeDiff{
+				Attribute: attr,
+				Old:       old,
+				New:       new,
+			})
+		}
+	}
+
+	add("next-hop", b.nextHopDiffString(), other.nextHopDiffString())
+	add("local-pref", fmt.Sprintf("%d", b.BGPPathA.LocalPref), fmt.Sprintf("%d", other.BGPPathA.LocalPref))
+	add("med", fmt.Sprintf("%d", b.BGPPathA.MED), fmt.Sprintf("%d", other.BGPPathA.MED))
+	add("origin", b.originCode(), other.originCode())
+	add("as-path", b.ASPath.String(), other.ASPath.String())
+	add("communities", b.communitiesDiffString(), other.communitiesDiffString())
+	add("large-communities", b.largeCommunitiesDiffString(), other.largeCommunitiesDiffString())
+
+	return ret
+}
+
+func (b *BGPPath) nextHopDiffString() string {
+	if b.BGPPathA.NextHop == nil {
+		return ""
+	}
+
+	return b.BGPPathA.NextHop.String()
+}
+
+func (b *BGPPath) communitiesDiffString() string {
+	if b.Communities == nil {
+		return ""
+	}
+
+	return b.CommunitiesString()
+}
+
+func (b *BGPPath) largeCommunitiesDiffString() string {
+	if b.LargeCommunities == nil {
+		return ""
+	}
+
+	return b.LargeCommunitiesString()
+}
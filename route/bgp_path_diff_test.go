@@ -0,0 +1,84 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunSet(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				LocalPref: 100,
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{65001},
+				},
+			},
+			Communities: &types.Communities{65001<<16 | 1},
+		}
+	}
+
+	lp := uint32(200)
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		actions  SetActions
+		expected []AttributeDiff
+	}{
+		{
+			name:     "No actions",
+			path:     newPath(),
+			expected: []AttributeDiff{},
+		},
+		{
+			name: "Set local pref and add community",
+			path: newPath(),
+			actions: SetActions{
+				LocalPref:      &lp,
+				AddCommunities: []uint32{65001<<16 | 2},
+			},
+			expected: []AttributeDiff{
+				{
+					Attribute: "local-pref",
+					Old:       "100",
+					New:       "200",
+				},
+				{
+					Attribute: "communities",
+					Old:       "(65001,1)",
+					New:       "(65001,1) (65001,2)",
+				},
+			},
+		},
+		{
+			name: "Add community to path without communities",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+				ASPath:   &types.ASPath{},
+			},
+			actions: SetActions{
+				AddCommunities: []uint32{65001<<16 | 2},
+			},
+			expected: []AttributeDiff{
+				{
+					Attribute: "communities",
+					Old:       "",
+					New:       "(65001,2)",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		orig := test.path.Copy()
+		assert.Equal(t, test.expected, test.path.DryRunSet(test.actions), test.name)
+		assert.Equal(t, orig, test.path, test.name)
+	}
+}
@@ -0,0 +1,246 @@
+package route
+
+import (
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// RedactOptions defines which attributes are removed by Redact
+type RedactOptions struct {
+	Source       bool // Source is replaced by the zero address of its family
+	OriginatorID bool
+	ClusterList  bool
+	Communities  bool // Standard, large and extended communities
+}
+
+// Redact returns a copy of the path with the attributes selected in opts removed. b is not modified.
+func (b *BGPPath) Redact(opts RedactOptions) *BGPPath {
+	cp := b.Copy()
+
+	pa := *b.BGPPathA
+	cp.BGPPathA = &pa
+
+	if opts.Source {
+		cp.BGPPathA.Source = bnet.IPv4(0).Ptr()
+		if b.BGPPathA.Source != nil && !b.BGPPathA.Source.IsIPv4() {
+			cp.BGPPathA.Source = bnet.IPv6(0, 0).Ptr()
+		}
+	}
+
+	if opts.OriginatorID {
+		cp.BGPPathA.OriginatorID = 0
+	}
+
+	if opts.ClusterList {
+		cp.ClusterList = nil
+	}
+
+	if opts.Communities {
+		cp.Communities = nil
+		cp.LargeCommunities = nil
+		cp.ExtendedCommunities = nil
+	}
+
+	return cp
+}
+
+// SetMEDFromIGPMetric sets the MED to the IGP metric towards the next hop ("set metric igp").
+// The MED is marked present, so it is sent even if metric is zero.
+// BGPPathA might be shared with other paths, thus it is copied before being modified.
+func (b *BGPPath) SetMEDFromIGPMetric(metric uint32) {
+	pa := *b.BGPPathA
+	pa.MED = metric
+	pa.HasMED = true
+	b.BGPPathA = &pa
+}
+
+// LocalASOptions modifies the behavior of ApplyLocalAS and ApplyLocalASImport
+type LocalASOptions struct {
+	// NoPrepend omits prepending the local AS to paths received from the neighbor (see ApplyLocalASImport)
+	NoPrepend bool
+
+	// ReplaceAS omits the real AS on paths advertised to the neighbor, only the local AS is prepended
+	ReplaceAS bool
+}
+
+// ApplyLocalAS prepends the AS path of a path advertised to a neighbor that peers with localAS instead of
+// our real AS (local-as). By default both ASNs are prepended resulting in "localAS realAS ...".
+// The local AS is always prepended as the neighbor expects it as first AS.
+func (b *BGPPath) ApplyLocalAS(localAS, realAS uint32, opts LocalASOptions) {
+	if b.ASPath == nil {
+		b.ASPath = &types.ASPath{}
+	}
+
+	if !opts.ReplaceAS {
+		b.Prepend(realAS, 1)
+	}
+
+	b.Prepend(localAS, 1)
+}
+
+// ApplyLocalASImport prepends the local AS to a path received from a neighbor that peers with localAS
+// instead of our real AS (local-as), unless opts.NoPrepend is set
+func (b *BGPPath) ApplyLocalASImport(localAS uint32, opts LocalASOptions) {
+	if opts.NoPrepend {
+		return
+	}
+
+	if b.ASPath == nil {
+		b.ASPath = &types.ASPath{}
+	}
+
+	b.Prepend(localAS, 1)
+}
+
+// NextHopSelfIf sets the next hop to self if cond is true for b
+func (b *BGPPath) NextHopSelfIf(cond func(*BGPPath) bool, self *bnet.IP) {
+	if !cond(b) {
+		return
+	}
+
+	pa := *b.BGPPathA
+	pa.NextHop = self
+	b.BGPPathA = &pa
+}
+
+// RestrictCommunities returns a copy of the path only keeping the communities, large communities and
+// extended communities contained in whitelist. b is not modified.
+func (b *BGPPath) RestrictCommunities(whitelist CommunityList) *BGPPath {
+	cp := b.Copy()
+
+	if b.Communities != nil {
+		coms := make(types.Communities, 0, len(*b.Communities))
+		for _, com := range *b.Communities {
+			if whitelist.ContainsCommunity(com) {
+				coms = append(coms, com)
+			}
+		}
+
+		cp.Communities = &coms
+	}
+
+	if b.LargeCommunities != nil {
+		lcoms := make(types.LargeCommunities, 0, len(*b.LargeCommunities))
+		for _, com := range *b.LargeCommunities {
+			if whitelist.ContainsLargeCommunity(com) {
+				lcoms = append(lcoms, com)
+			}
+		}
+
+		cp.LargeCommunities = &lcoms
+	}
+
+	if b.ExtendedCommunities != nil {
+		ecoms := make([]types.ExtendedCommunity, 0, len(*b.ExtendedCommunities))
+		for _, com := range *b.ExtendedCommunities {
+			if whitelist.ContainsExtendedCommunity(com) {
+				ecoms = append(ecoms, com)
+			}
+		}
+
+		cp.ExtendedCommunities = &ecoms
+	}
+
+	return cp
+}
+
+// IsThirdPartyNextHop checks if the next hop is none of selfAddresses
+func (b *BGPPath) IsThirdPartyNextHop(selfAddresses []bnet.IP) bool {
+	if b.BGPPathA.NextHop == nil {
+		return false
+	}
+
+	for _, addr := range selfAddresses {
+		if *b.BGPPathA.NextHop == addr {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ThirdPartyNextHopMode defines how third party next hops are handled on export
+type ThirdPartyNextHopMode uint8
+
+const (
+	// ThirdPartyNextHopRewrite rewrites third party next hops to self
+	ThirdPartyNextHopRewrite ThirdPartyNextHopMode = iota
+	// ThirdPartyNextHopPreserve keeps third party next hops unchanged (e.g. on route servers)
+	ThirdPartyNextHopPreserve
+)
+
+// SetExportNextHop sets the next hop to self unless it is a third party next hop which is to be preserved
+func (b *BGPPath) SetExportNextHop(self *bnet.IP, selfAddresses []bnet.IP, mode ThirdPartyNextHopMode) {
+	b.NextHopSelfIf(func(p *BGPPath) bool {
+		return mode == ThirdPartyNextHopRewrite || !p.IsThirdPartyNextHop(selfAddresses)
+	}, self)
+}
+
+// PeerCapabilities describes which optional attributes a peer is able to process
+type PeerCapabilities struct {
+	LargeCommunities    bool
+	ExtendedCommunities bool
+}
+
+// StripForCapabilities returns a copy of the path omitting attributes the peer described by caps can not
+// process. b is not modified.
+func (b *BGPPath) StripForCapabilities(caps PeerCapabilities) *BGPPath {
+	cp := b.Copy()
+
+	if !caps.LargeCommunities {
+		cp.LargeCommunities = nil
+	}
+
+	if !caps.ExtendedCommunities {
+		cp.ExtendedCommunities = nil
+	}
+
+	if !caps.ExtendedCommunities && len(b.UnknownAttributes) > 0 {
+		cp.UnknownAttributes = make([]types.UnknownPathAttribute, 0, len(b.UnknownAttributes))
+		for _, attr := range b.UnknownAttributes {
+			if attr.TypeCode == types.ExtendedCommunitiesAttr {
+				continue
+			}
+
+			cp.UnknownAttributes = append(cp.UnknownAttributes, attr)
+		}
+	}
+
+	return cp
+}
+
+// AdvertisableToIBGP checks if the path may be advertised to an iBGP peer (iBGP split horizon). Paths learned
+// via iBGP are only advertised if we act as route reflector (RFC4456) for the peer.
+// Paths redistributed from other protocols are always advertisable.
+func (b *BGPPath) AdvertisableToIBGP(isRouteReflector bool) bool {
+	return isRouteReflector || b.BGPPathA.EBGP || b.SourceProtocol != SourceProtocolBGP
+}
+
+// EBGPExportOptions modifies the behavior of PrepareEBGPExport
+type EBGPExportOptions struct {
+	// PreserveMEDToSameAS keeps the MED of paths advertised back to the AS they were learned from
+	PreserveMEDToSameAS bool
+}
+
+// PrepareEBGPExport modifies the path for advertisement to an eBGP neighbor in neighborAS: localAS is
+// prepended and the next hop is set to nextHop. As a MED must not be propagated to other neighboring ASes
+// (RFC4271 5.1.4) it is removed unless opts allow to keep it.
+// The AS path is modified in place, thus callers are expected to work on a Copy().
+func (b *BGPPath) PrepareEBGPExport(localAS, neighborAS uint32, nextHop *bnet.IP, opts EBGPExportOptions) {
+	pa := *b.BGPPathA
+
+	learnedFrom, ok := b.NeighborAS()
+	if !opts.PreserveMEDToSameAS || !ok || learnedFrom != neighborAS {
+		pa.MED = 0
+		pa.HasMED = false
+	}
+
+	pa.NextHop = nextHop
+	b.BGPPathA = &pa
+
+	if b.ASPath == nil {
+		b.ASPath = &types.ASPath{}
+	}
+
+	b.Prepend(localAS, 1)
+}
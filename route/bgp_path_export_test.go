@@ -0,0 +1,608 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRedactPath() *BGPPath {
+	return &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:      bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:       bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+			OriginatorID: 23,
+			LocalPref:    100,
+		},
+		ASPath:      &types.ASPath{},
+		ClusterList: &types.ClusterList{10, 20},
+		Communities: &types.Communities{100},
+		LargeCommunities: &types.LargeCommunities{
+			{
+				GlobalAdministrator: 1,
+				DataPart1:           2,
+				DataPart2:           3,
+			},
+		},
+		ExtendedCommunities: &[]types.ExtendedCommunity{
+			{
+				Type:    types.ExtendedCommunityTypeTwoOctetAS,
+				SubType: types.ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+		},
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     RedactOptions
+		expected func() *BGPPath
+	}{
+		{
+			name: "Nothing",
+			opts: RedactOptions{},
+			expected: func() *BGPPath {
+				return testRedactPath()
+			},
+		},
+		{
+			name: "Source",
+			opts: RedactOptions{
+				Source: true,
+			},
+			expected: func() *BGPPath {
+				p := testRedactPath()
+				p.BGPPathA.Source = bnet.IPv4(0).Ptr()
+				return p
+			},
+		},
+		{
+			name: "OriginatorID",
+			opts: RedactOptions{
+				OriginatorID: true,
+			},
+			expected: func() *BGPPath {
+				p := testRedactPath()
+				p.BGPPathA.OriginatorID = 0
+				return p
+			},
+		},
+		{
+			name: "ClusterList",
+			opts: RedactOptions{
+				ClusterList: true,
+			},
+			expected: func() *BGPPath {
+				p := testRedactPath()
+				p.ClusterList = nil
+				return p
+			},
+		},
+		{
+			name: "Communities",
+			opts: RedactOptions{
+				Communities: true,
+			},
+			expected: func() *BGPPath {
+				p := testRedactPath()
+				p.Communities = nil
+				p.LargeCommunities = nil
+				p.ExtendedCommunities = nil
+				return p
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p := testRedactPath()
+		res := p.Redact(test.opts)
+
+		assert.Equal(t, test.expected(), res, test.name)
+		assert.Equal(t, testRedactPath(), p, test.name)
+	}
+}
+
+func TestRedactIPv6Source(t *testing.T) {
+	p := testRedactPath()
+	p.BGPPathA.Source = bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr()
+
+	res := p.Redact(RedactOptions{
+		Source: true,
+	})
+	assert.Equal(t, bnet.IPv6(0, 0).Ptr(), res.BGPPathA.Source)
+	assert.False(t, res.BGPPathA.Source.IsIPv4())
+}
+
+func TestSetMEDFromIGPMetric(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		metric   uint32
+		expected uint32
+	}{
+		{
+			name: "No MED set",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+			},
+			metric:   100,
+			expected: 100,
+		},
+		{
+			name: "MED overwritten",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					MED: 500,
+				},
+			},
+			metric:   20,
+			expected: 20,
+		},
+		{
+			name: "Zero metric",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					MED: 500,
+				},
+			},
+			metric:   0,
+			expected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		orig := test.path.BGPPathA
+		origMED := orig.MED
+
+		test.path.SetMEDFromIGPMetric(test.metric)
+		assert.Equal(t, test.expected, test.path.BGPPathA.MED, test.name)
+		assert.True(t, test.path.BGPPathA.MEDPresent(), test.name)
+		assert.Equal(t, origMED, orig.MED, test.name+": shared BGPPathA modified")
+		assert.False(t, orig.HasMED, test.name+": shared BGPPathA modified")
+	}
+}
+
+func TestApplyLocalAS(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LocalASOptions
+		expected []uint32
+	}{
+		{
+			name:     "Default",
+			expected: []uint32{65000, 201701, 3320},
+		},
+		{
+			name: "No prepend does not affect export",
+			opts: LocalASOptions{
+				NoPrepend: true,
+			},
+			expected: []uint32{65000, 201701, 3320},
+		},
+		{
+			name: "Replace AS",
+			opts: LocalASOptions{
+				ReplaceAS: true,
+			},
+			expected: []uint32{65000, 3320},
+		},
+		{
+			name: "No prepend and replace AS",
+			opts: LocalASOptions{
+				NoPrepend: true,
+				ReplaceAS: true,
+			},
+			expected: []uint32{65000, 3320},
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{3320},
+				},
+			},
+			ASPathLen: 1,
+		}
+
+		p.ApplyLocalAS(65000, 201701, test.opts)
+		assert.Equal(t, test.expected, (*p.ASPath)[0].ASNs, test.name)
+		assert.Equal(t, uint16(len(test.expected)), p.ASPathLen, test.name)
+	}
+}
+
+func TestApplyLocalASImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LocalASOptions
+		expected []uint32
+	}{
+		{
+			name:     "Default",
+			expected: []uint32{65000, 3320},
+		},
+		{
+			name: "No prepend",
+			opts: LocalASOptions{
+				NoPrepend: true,
+			},
+			expected: []uint32{3320},
+		},
+		{
+			name: "Replace AS does not affect import",
+			opts: LocalASOptions{
+				ReplaceAS: true,
+			},
+			expected: []uint32{65000, 3320},
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{3320},
+				},
+			},
+			ASPathLen: 1,
+		}
+
+		p.ApplyLocalASImport(65000, test.opts)
+		assert.Equal(t, test.expected, (*p.ASPath)[0].ASNs, test.name)
+		assert.Equal(t, uint16(len(test.expected)), p.ASPathLen, test.name)
+	}
+}
+
+func TestNextHopSelfIf(t *testing.T) {
+	self := bnet.IPv4FromOctets(192, 168, 0, 1).Ptr()
+	ebgpOnly := func(p *BGPPath) bool {
+		return p.BGPPathA.EBGP
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected *bnet.IP
+	}{
+		{
+			name: "eBGP learned path is rewritten",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					EBGP:    true,
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+			},
+			expected: self,
+		},
+		{
+			name: "iBGP learned path is kept",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+			},
+			expected: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+	}
+
+	for _, test := range tests {
+		orig := test.path.BGPPathA
+		origNextHop := orig.NextHop
+
+		test.path.NextHopSelfIf(ebgpOnly, self)
+		assert.Equal(t, test.expected, test.path.BGPPathA.NextHop, test.name)
+		assert.Equal(t, origNextHop, orig.NextHop, test.name+": shared BGPPathA modified")
+	}
+}
+
+func TestRestrictCommunities(t *testing.T) {
+	rt := types.ExtendedCommunity{
+		Type:    types.ExtendedCommunityTypeTwoOctetAS,
+		SubType: types.ExtendedCommunitySubTypeRouteTarget,
+		Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+	}
+	ro := types.ExtendedCommunity{
+		Type:    types.ExtendedCommunityTypeTwoOctetAS,
+		SubType: types.ExtendedCommunitySubTypeRouteOrigin,
+		Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+	}
+
+	whitelist := CommunityList{
+		Communities: []uint32{65000<<16 + 1, 65000<<16 + 2},
+		LargeCommunities: []types.LargeCommunity{
+			{
+				GlobalAdministrator: 65000,
+				DataPart1:           1,
+				DataPart2:           1,
+			},
+		},
+		ExtendedCommunities: []types.ExtendedCommunity{rt},
+	}
+
+	p := &BGPPath{
+		BGPPathA:    &BGPPathA{},
+		Communities: &types.Communities{65000<<16 + 1, 65001<<16 + 1, 65000<<16 + 2},
+		LargeCommunities: &types.LargeCommunities{
+			{
+				GlobalAdministrator: 65000,
+				DataPart1:           1,
+				DataPart2:           1,
+			},
+			{
+				GlobalAdministrator: 65000,
+				DataPart1:           2,
+				DataPart2:           1,
+			},
+		},
+		ExtendedCommunities: &[]types.ExtendedCommunity{ro, rt},
+	}
+
+	res := p.RestrictCommunities(whitelist)
+	assert.Equal(t, &[]types.ExtendedCommunity{rt}, res.ExtendedCommunities)
+	assert.Equal(t, &[]types.ExtendedCommunity{}, (&BGPPath{ExtendedCommunities: &[]types.ExtendedCommunity{rt}}).RestrictCommunities(CommunityList{}).ExtendedCommunities)
+	assert.Equal(t, &types.Communities{65000<<16 + 1, 65000<<16 + 2}, res.Communities)
+	assert.Equal(t, &types.LargeCommunities{
+		{
+			GlobalAdministrator: 65000,
+			DataPart1:           1,
+			DataPart2:           1,
+		},
+	}, res.LargeCommunities)
+
+	assert.Equal(t, &types.Communities{65000<<16 + 1, 65001<<16 + 1, 65000<<16 + 2}, p.Communities)
+	assert.Len(t, *p.LargeCommunities, 2)
+
+	assert.Equal(t, &types.Communities{}, (&BGPPath{Communities: &types.Communities{1}}).RestrictCommunities(CommunityList{}).Communities)
+	assert.Nil(t, (&BGPPath{}).RestrictCommunities(whitelist).Communities)
+}
+
+func TestIsThirdPartyNextHop(t *testing.T) {
+	selfAddresses := []bnet.IP{
+		bnet.IPv4FromOctets(192, 168, 0, 1),
+		bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1),
+	}
+
+	tests := []struct {
+		name     string
+		nextHop  *bnet.IP
+		expected bool
+	}{
+		{
+			name:     "Third party next hop",
+			nextHop:  bnet.IPv4FromOctets(192, 168, 0, 2).Ptr(),
+			expected: true,
+		},
+		{
+			name:     "Self next hop",
+			nextHop:  bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			expected: false,
+		},
+		{
+			name:     "IPv6 self next hop",
+			nextHop:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: test.nextHop,
+			},
+		}
+
+		assert.Equal(t, test.expected, p.IsThirdPartyNextHop(selfAddresses), test.name)
+	}
+}
+
+func TestSetExportNextHop(t *testing.T) {
+	self := bnet.IPv4FromOctets(192, 168, 0, 1).Ptr()
+	selfAddresses := []bnet.IP{*self}
+
+	tests := []struct {
+		name     string
+		nextHop  *bnet.IP
+		mode     ThirdPartyNextHopMode
+		expected *bnet.IP
+	}{
+		{
+			name:     "Third party next hop preserved",
+			nextHop:  bnet.IPv4FromOctets(192, 168, 0, 2).Ptr(),
+			mode:     ThirdPartyNextHopPreserve,
+			expected: bnet.IPv4FromOctets(192, 168, 0, 2).Ptr(),
+		},
+		{
+			name:     "Third party next hop rewritten",
+			nextHop:  bnet.IPv4FromOctets(192, 168, 0, 2).Ptr(),
+			mode:     ThirdPartyNextHopRewrite,
+			expected: self,
+		},
+		{
+			name:     "Self next hop",
+			nextHop:  self,
+			mode:     ThirdPartyNextHopPreserve,
+			expected: self,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: test.nextHop,
+			},
+		}
+
+		p.SetExportNextHop(self, selfAddresses, test.mode)
+		assert.Equal(t, test.expected, p.BGPPathA.NextHop, test.name)
+	}
+}
+
+func TestStripForCapabilities(t *testing.T) {
+	extCommunities := types.UnknownPathAttribute{
+		Optional:   true,
+		Transitive: true,
+		TypeCode:   16,
+		Value:      []byte{0x03, 0x0b, 0, 0, 0, 0, 0, 100},
+	}
+	otherAttr := types.UnknownPathAttribute{
+		Optional:   true,
+		Transitive: true,
+		TypeCode:   233,
+		Value:      []byte{1, 2, 3},
+	}
+
+	tests := []struct {
+		name                      string
+		caps                      PeerCapabilities
+		expectedLargeCommunities  *types.LargeCommunities
+		expectedUnknownAttributes []types.UnknownPathAttribute
+	}{
+		{
+			name: "All capabilities",
+			caps: PeerCapabilities{
+				LargeCommunities:    true,
+				ExtendedCommunities: true,
+			},
+			expectedLargeCommunities:  &types.LargeCommunities{{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3}},
+			expectedUnknownAttributes: []types.UnknownPathAttribute{extCommunities, otherAttr},
+		},
+		{
+			name: "No large communities",
+			caps: PeerCapabilities{
+				ExtendedCommunities: true,
+			},
+			expectedUnknownAttributes: []types.UnknownPathAttribute{extCommunities, otherAttr},
+		},
+		{
+			name:                      "Legacy peer",
+			caps:                      PeerCapabilities{},
+			expectedUnknownAttributes: []types.UnknownPathAttribute{otherAttr},
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA:          &BGPPathA{},
+			Communities:       &types.Communities{100},
+			LargeCommunities:  &types.LargeCommunities{{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3}},
+			UnknownAttributes: []types.UnknownPathAttribute{extCommunities, otherAttr},
+		}
+
+		res := p.StripForCapabilities(test.caps)
+		assert.Equal(t, test.expectedLargeCommunities, res.LargeCommunities, test.name)
+		assert.Equal(t, test.expectedUnknownAttributes, res.UnknownAttributes, test.name)
+		assert.Equal(t, &types.Communities{100}, res.Communities, test.name)
+		assert.Equal(t, 1, len(*p.LargeCommunities), test.name)
+		assert.Equal(t, 2, len(p.UnknownAttributes), test.name)
+	}
+}
+
+func TestAdvertisableToIBGP(t *testing.T) {
+	tests := []struct {
+		name             string
+		ebgp             bool
+		sourceProtocol   SourceProtocol
+		isRouteReflector bool
+		expected         bool
+	}{
+		{
+			name:     "eBGP learned, no RR",
+			ebgp:     true,
+			expected: true,
+		},
+		{
+			name:             "eBGP learned, RR",
+			ebgp:             true,
+			isRouteReflector: true,
+			expected:         true,
+		},
+		{
+			name:     "iBGP learned, no RR",
+			expected: false,
+		},
+		{
+			name:             "iBGP learned, RR",
+			isRouteReflector: true,
+			expected:         true,
+		},
+		{
+			name:           "Redistributed static route",
+			sourceProtocol: SourceProtocolStatic,
+			expected:       true,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				EBGP: test.ebgp,
+			},
+			SourceProtocol: test.sourceProtocol,
+		}
+
+		assert.Equal(t, test.expected, p.AdvertisableToIBGP(test.isRouteReflector), test.name)
+	}
+}
+
+func TestPrepareEBGPExport(t *testing.T) {
+	self := bnet.IPv4FromOctets(192, 168, 0, 1).Ptr()
+
+	tests := []struct {
+		name        string
+		neighborAS  uint32
+		opts        EBGPExportOptions
+		expectedMED uint32
+	}{
+		{
+			name:       "MED stripped by default",
+			neighborAS: 65001,
+		},
+		{
+			name:       "MED preserved to same AS",
+			neighborAS: 65001,
+			opts: EBGPExportOptions{
+				PreserveMEDToSameAS: true,
+			},
+			expectedMED: 42,
+		},
+		{
+			name:       "MED stripped towards other AS",
+			neighborAS: 65002,
+			opts: EBGPExportOptions{
+				PreserveMEDToSameAS: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		pa := &BGPPathA{
+			MED:     42,
+			HasMED:  true,
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		}
+		p := &BGPPath{
+			BGPPathA: pa,
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{65001, 201701},
+				},
+			},
+			ASPathLen: 2,
+		}
+
+		p.PrepareEBGPExport(65000, test.neighborAS, self, test.opts)
+		assert.Equal(t, test.expectedMED, p.BGPPathA.MED, test.name)
+		assert.Equal(t, test.expectedMED != 0, p.BGPPathA.MEDPresent(), test.name)
+		assert.Equal(t, self, p.BGPPathA.NextHop, test.name)
+		assert.Equal(t, "65000 65001 201701", p.ASPath.String(), test.name)
+		assert.Equal(t, uint32(42), pa.MED, test.name)
+	}
+}
@@ -0,0 +1,140 @@
+package route
+
+import (
+	"fmt"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// CommunityLimitMode defines how LimitCommunitiesOnImport handles paths exceeding the limits
+type CommunityLimitMode uint8
+
+const (
+	// CommunityLimitTruncate drops all communities beyond the limit
+	CommunityLimitTruncate CommunityLimitMode = iota
+	// CommunityLimitReject rejects paths exceeding the limit
+	CommunityLimitReject
+)
+
+// LimitCommunitiesOnImport limits the number of communities and large communities of the path.
+// In truncate mode a copy keeping only the first maxStd communities and maxLarge large communities is returned.
+// In reject mode an error is returned if a limit is exceeded. Negative limits are invalid. b is never modified.
+func (b *BGPPath) LimitCommunitiesOnImport(maxStd, maxLarge int, mode CommunityLimitMode) (*BGPPath, error) {
+	if maxStd < 0 || maxLarge < 0 {
+		return nil, fmt.Errorf("invalid community limits: %d communities, %d large communities", maxStd, maxLarge)
+	}
+
+	stdExceeded := b.Communities != nil && len(*b.Communities) > maxStd
+	largeExceeded := b.LargeCommunities != nil && len(*b.LargeCommunities) > maxLarge
+
+	if !stdExceeded && !largeExceeded {
+		return b, nil
+	}
+
+	if mode == CommunityLimitReject {
+		if stdExceeded {
+			return nil, fmt.Errorf("%d communities exceed the limit of %d", len(*b.Communities), maxStd)
+		}
+
+		return nil, fmt.Errorf("%d large communities exceed the limit of %d", len(*b.LargeCommunities), maxLarge)
+	}
+
+	cp := b.Copy()
+	if stdExceeded {
+		coms := make(types.Communities, maxStd)
+		copy(coms, *b.Communities)
+		cp.Communities = &coms
+	}
+
+	if largeExceeded {
+		lcoms := make(types.LargeCommunities, maxLarge)
+		copy(lcoms, *b.LargeCommunities)
+		cp.LargeCommunities = &lcoms
+	}
+
+	return cp, nil
+}
+
+// CommunityAction adds and removes communities and large communities. Removals are applied before additions.
+type CommunityAction struct {
+	Add         []uint32
+	Remove      []uint32
+	AddLarge    []types.LargeCommunity
+	RemoveLarge []types.LargeCommunity
+}
+
+// ApplyCommunityAction applies a to b. New slices are allocated, so slices shared with other paths are not modified.
+// If source is not empty and the communities were changed, source is recorded in CommunitiesSetBy.
+func (b *BGPPath) ApplyCommunityAction(a CommunityAction, source string) {
+	changed := false
+
+	if len(a.Add) > 0 || len(a.Remove) > 0 {
+		stdChanged := false
+		coms := make(types.Communities, 0)
+		if b.Communities != nil {
+			for _, com := range *b.Communities {
+				if containsUint32(a.Remove, com) {
+					stdChanged = true
+					continue
+				}
+
+				coms = append(coms, com)
+			}
+		}
+
+		for _, com := range a.Add {
+			if !containsUint32(coms, com) {
+				coms = append(coms, com)
+				stdChanged = true
+			}
+		}
+
+		if stdChanged {
+			b.Communities = &coms
+			changed = true
+		}
+	}
+
+	if len(a.AddLarge) > 0 || len(a.RemoveLarge) > 0 {
+		largeChanged := false
+		remove := CommunityList{LargeCommunities: a.RemoveLarge}
+		lcoms := make(types.LargeCommunities, 0)
+		if b.LargeCommunities != nil {
+			for _, com := range *b.LargeCommunities {
+				if remove.ContainsLargeCommunity(com) {
+					largeChanged = true
+					continue
+				}
+
+				lcoms = append(lcoms, com)
+			}
+		}
+
+		for _, com := range a.AddLarge {
+			present := CommunityList{LargeCommunities: lcoms}
+			if !present.ContainsLargeCommunity(com) {
+				lcoms = append(lcoms, com)
+				largeChanged = true
+			}
+		}
+
+		if largeChanged {
+			b.LargeCommunities = &lcoms
+			changed = true
+		}
+	}
+
+	if changed && source != "" {
+		b.CommunitiesSetBy = source
+	}
+}
+
+func containsUint32(s []uint32, x uint32) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,184 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitCommunitiesOnImport(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			Communities: &types.Communities{1, 2, 3},
+			LargeCommunities: &types.LargeCommunities{
+				{GlobalAdministrator: 1},
+				{GlobalAdministrator: 2},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		maxStd   int
+		maxLarge int
+		mode     CommunityLimitMode
+		expected *BGPPath
+		wantFail bool
+	}{
+		{
+			name:     "Within limits",
+			maxStd:   3,
+			maxLarge: 2,
+			mode:     CommunityLimitReject,
+			expected: newPath(),
+		},
+		{
+			name:     "Truncate",
+			maxStd:   2,
+			maxLarge: 1,
+			mode:     CommunityLimitTruncate,
+			expected: &BGPPath{
+				Communities: &types.Communities{1, 2},
+				LargeCommunities: &types.LargeCommunities{
+					{GlobalAdministrator: 1},
+				},
+			},
+		},
+		{
+			name:     "Reject communities",
+			maxStd:   2,
+			maxLarge: 2,
+			mode:     CommunityLimitReject,
+			wantFail: true,
+		},
+		{
+			name:     "Reject large communities",
+			maxStd:   3,
+			maxLarge: 1,
+			mode:     CommunityLimitReject,
+			wantFail: true,
+		},
+		{
+			name:     "Negative community limit",
+			maxStd:   -1,
+			maxLarge: 2,
+			mode:     CommunityLimitTruncate,
+			wantFail: true,
+		},
+		{
+			name:     "Negative large community limit",
+			maxStd:   3,
+			maxLarge: -1,
+			mode:     CommunityLimitTruncate,
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		p := newPath()
+		res, err := p.LimitCommunitiesOnImport(test.maxStd, test.maxLarge, test.mode)
+		assert.Equal(t, newPath(), p, test.name)
+
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, res, test.name)
+	}
+}
+
+func TestApplyCommunityAction(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          *BGPPath
+		action        CommunityAction
+		source        string
+		expected      *types.Communities
+		expectedLarge *types.LargeCommunities
+		expectedSetBy string
+	}{
+		{
+			name: "Add and remove",
+			path: &BGPPath{
+				Communities: &types.Communities{1, 2},
+			},
+			action: CommunityAction{
+				Add:    []uint32{3, 1},
+				Remove: []uint32{2},
+			},
+			source:        "import-transit/tag",
+			expected:      &types.Communities{1, 3},
+			expectedSetBy: "import-transit/tag",
+		},
+		{
+			name: "Large communities",
+			path: &BGPPath{
+				CommunitiesSetBy: "old",
+				LargeCommunities: &types.LargeCommunities{
+					{GlobalAdministrator: 1},
+				},
+			},
+			action: CommunityAction{
+				AddLarge:    []types.LargeCommunity{{GlobalAdministrator: 2}},
+				RemoveLarge: []types.LargeCommunity{{GlobalAdministrator: 1}},
+			},
+			source: "import-peer/large",
+			expectedLarge: &types.LargeCommunities{
+				{GlobalAdministrator: 2},
+			},
+			expectedSetBy: "import-peer/large",
+		},
+		{
+			name: "No change keeps provenance",
+			path: &BGPPath{
+				Communities:      &types.Communities{1},
+				CommunitiesSetBy: "old",
+			},
+			action: CommunityAction{
+				Add:    []uint32{1},
+				Remove: []uint32{5},
+			},
+			source:        "new",
+			expected:      &types.Communities{1},
+			expectedSetBy: "old",
+		},
+		{
+			name: "No source",
+			path: &BGPPath{},
+			action: CommunityAction{
+				Add: []uint32{1},
+			},
+			expected: &types.Communities{1},
+		},
+	}
+
+	for _, test := range tests {
+		test.path.ApplyCommunityAction(test.action, test.source)
+		assert.Equal(t, test.expected, test.path.Communities, test.name)
+		assert.Equal(t, test.expectedLarge, test.path.LargeCommunities, test.name)
+		assert.Equal(t, test.expectedSetBy, test.path.CommunitiesSetBy, test.name)
+	}
+}
+
+func TestCommunitiesSetByNotCompared(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4(0).Ptr(),
+			Source:  bnet.IPv4(0).Ptr(),
+		},
+		ASPath:      &types.ASPath{},
+		Communities: &types.Communities{1},
+	}
+
+	q := p.Copy()
+	q.CommunitiesSetBy = "import/tag"
+
+	assert.Equal(t, "import/tag", q.Copy().CommunitiesSetBy)
+	assert.True(t, p.Equal(q))
+	assert.True(t, p.AttributesEqual(q))
+	assert.Equal(t, p.ComputeHash(), q.ComputeHash())
+}
@@ -0,0 +1,90 @@
+package route
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+type bgpPathJSON struct {
+	PathIdentifier   uint32   `json:"path_identifier"`
+	NextHop          string   `json:"next_hop"`
+	LocalPref        uint32   `json:"local_pref"`
+	ASPath           string   `json:"as_path"`
+	Origin           uint8    `json:"origin"`
+	MED              uint32   `json:"med"`
+	EBGP             bool     `json:"ebgp"`
+	BGPIdentifier    uint32   `json:"bgp_identifier"`
+	Source           string   `json:"source"`
+	Communities      []string `json:"communities,omitempty"`
+	LargeCommunities []string `json:"large_communities,omitempty"`
+	OriginatorID     uint32   `json:"originator_id,omitempty"`
+	ClusterList      []uint32 `json:"cluster_list,omitempty"`
+}
+
+type bgpPathJSONLRecord struct {
+	Prefix string   `json:"prefix"`
+	Path   *BGPPath `json:"path"`
+}
+
+// MarshalJSON encodes the path as JSON. Addresses, the AS path and communities are rendered human readable.
+func (b *BGPPath) MarshalJSON() ([]byte, error) {
+	x := bgpPathJSON{
+		PathIdentifier: b.PathIdentifier,
+		LocalPref:      b.BGPPathA.LocalPref,
+		ASPath:         b.ASPath.String(),
+		Origin:         b.BGPPathA.Origin,
+		MED:            b.BGPPathA.MED,
+		EBGP:           b.BGPPathA.EBGP,
+		BGPIdentifier:  b.BGPPathA.BGPIdentifier,
+		OriginatorID:   b.BGPPathA.OriginatorID,
+	}
+
+	if b.BGPPathA.NextHop != nil {
+		x.NextHop = b.BGPPathA.NextHop.String()
+	}
+
+	if b.BGPPathA.Source != nil {
+		x.Source = b.BGPPathA.Source.String()
+	}
+
+	if b.Communities != nil {
+		for _, com := range *b.Communities {
+			x.Communities = append(x.Communities, types.CommunityStringForUint32(com))
+		}
+	}
+
+	if b.LargeCommunities != nil {
+		for i := range *b.LargeCommunities {
+			x.LargeCommunities = append(x.LargeCommunities, (*b.LargeCommunities)[i].String())
+		}
+	}
+
+	if b.ClusterList != nil {
+		x.ClusterList = *b.ClusterList
+	}
+
+	return json.Marshal(x)
+}
+
+// WriteJSONL writes the path for prefix as a single line JSON object to w (JSON Lines)
+func (b *BGPPath) WriteJSONL(w io.Writer, prefix bnet.Prefix) error {
+	line, err := json.Marshal(bgpPathJSONLRecord{
+		Prefix: prefix.String(),
+		Path:   b,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal path: %w", err)
+	}
+
+	line = append(line, '\n')
+	_, err = w.Write(line)
+	if err != nil {
+		return fmt.Errorf("unable to write: %w", err)
+	}
+
+	return nil
+}
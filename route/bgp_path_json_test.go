@@ -0,0 +1,92 @@
+package route
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	p1 := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:    bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+			LocalPref: 100,
+			EBGP:      true,
+		},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{65001, 65002},
+			},
+		},
+		Communities: &types.Communities{65001<<16 + 100},
+		LargeCommunities: &types.LargeCommunities{
+			{
+				GlobalAdministrator: 1,
+				DataPart1:           2,
+				DataPart2:           3,
+			},
+		},
+	}
+	p2 := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 3).Ptr(),
+			MED:     10,
+		},
+		PathIdentifier: 2,
+	}
+
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, p1.WriteJSONL(buf, bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24)))
+	assert.NoError(t, p2.WriteJSONL(buf, bnet.NewPfx(bnet.IPv4FromOctets(198, 51, 100, 0), 24)))
+
+	expected := []map[string]interface{}{
+		{
+			"prefix": "192.0.2.0/24",
+			"path": map[string]interface{}{
+				"path_identifier":   float64(0),
+				"next_hop":          "10.0.0.1",
+				"local_pref":        float64(100),
+				"as_path":           "65001 65002",
+				"origin":            float64(0),
+				"med":               float64(0),
+				"ebgp":              true,
+				"bgp_identifier":    float64(0),
+				"source":            "10.0.0.2",
+				"communities":       []interface{}{"(65001,100)"},
+				"large_communities": []interface{}{"(1,2,3)"},
+			},
+		},
+		{
+			"prefix": "198.51.100.0/24",
+			"path": map[string]interface{}{
+				"path_identifier": float64(2),
+				"next_hop":        "10.0.0.3",
+				"local_pref":      float64(0),
+				"as_path":         "",
+				"origin":          float64(0),
+				"med":             float64(10),
+				"ebgp":            false,
+				"bgp_identifier":  float64(0),
+				"source":          "",
+			},
+		},
+	}
+
+	scanner := bufio.NewScanner(buf)
+	i := 0
+	for scanner.Scan() {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, expected[i], record)
+		i++
+	}
+
+	assert.Equal(t, len(expected), i)
+}
@@ -0,0 +1,35 @@
+package route
+
+import (
+	"math"
+
+	"github.com/bio-routing/tflow2/convert"
+)
+
+const (
+	// Link bandwidth extended community (draft-ietf-idr-link-bandwidth)
+	extCommunityTypeTwoOctetASNonTransitive = 0x40
+	extCommunitySubTypeLinkBandwidth        = 0x04
+	extCommunityLinkBandwidthValueIdx       = 2
+)
+
+// LinkBandwidth gets the sum of all link bandwidth extended communities of the path in bytes per second
+func (b *BGPPath) LinkBandwidth() (uint64, bool) {
+	sum := uint64(0)
+	found := false
+	for _, com := range b.extCommunities() {
+		if com.Type != extCommunityTypeTwoOctetASNonTransitive || com.SubType != extCommunitySubTypeLinkBandwidth {
+			continue
+		}
+
+		bw := math.Float32frombits(convert.Uint32b(com.Value[extCommunityLinkBandwidthValueIdx:]))
+		if math.IsNaN(float64(bw)) || bw < 0 {
+			continue
+		}
+
+		sum += uint64(bw)
+		found = true
+	}
+
+	return sum, found
+}
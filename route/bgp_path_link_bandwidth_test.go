@@ -0,0 +1,103 @@
+package route
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bio-routing/tflow2/convert"
+	"github.com/stretchr/testify/assert"
+)
+
+func linkBandwidthCommunity(bw float32) []byte {
+	return append([]byte{0x40, 0x04, 0xfd, 0xe8}, convert.Uint32Byte(math.Float32bits(bw))...)
+}
+
+func TestLinkBandwidth(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          *BGPPath
+		expected      uint64
+		expectedFound bool
+	}{
+		{
+			name: "No extended communities",
+			path: &BGPPath{},
+		},
+		{
+			name: "1G after route target",
+			path: colorPath(append([]byte{
+				0x00, 0x02, 0xfd, 0xe8, 0, 0, 0, 100, // Route Target 65000:100
+			}, linkBandwidthCommunity(125000000)...)),
+			expected:      125000000,
+			expectedFound: true,
+		},
+		{
+			name:          "Two communities are summed",
+			path:          colorPath(append(linkBandwidthCommunity(125000000), linkBandwidthCommunity(1250000000)...)),
+			expected:      1375000000,
+			expectedFound: true,
+		},
+		{
+			name:          "NaN is skipped",
+			path:          colorPath(append(linkBandwidthCommunity(float32(math.NaN())), linkBandwidthCommunity(125000000)...)),
+			expected:      125000000,
+			expectedFound: true,
+		},
+		{
+			name: "NaN",
+			path: colorPath(linkBandwidthCommunity(float32(math.NaN()))),
+		},
+	}
+
+	for _, test := range tests {
+		bw, found := test.path.LinkBandwidth()
+		assert.Equal(t, test.expected, bw, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+	}
+}
+
+func TestSelectLinkBandwidth(t *testing.T) {
+	slow := colorPath(linkBandwidthCommunity(125000000))
+	slow.BGPPathA.BGPIdentifier = 2
+	fast := colorPath(linkBandwidthCommunity(1250000000))
+	fast.BGPPathA.BGPIdentifier = 1
+	none := colorPath(nil)
+
+	tests := []struct {
+		name     string
+		b        *BGPPath
+		c        *BGPPath
+		opts     BGPSelectOptions
+		expected int8
+	}{
+		{
+			name:     "Flag not set, router ID decides",
+			b:        fast,
+			c:        slow,
+			expected: -1,
+		},
+		{
+			name: "Higher bandwidth wins",
+			b:    fast,
+			c:    slow,
+			opts: BGPSelectOptions{
+				CompareLinkBandwidth: true,
+			},
+			expected: 1,
+		},
+		{
+			name: "Path without bandwidth loses",
+			b:    none,
+			c:    slow,
+			opts: BGPSelectOptions{
+				CompareLinkBandwidth: true,
+			},
+			expected: -1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.b.SelectWithOptions(test.c, test.opts), test.name)
+		assert.Equal(t, -test.expected, test.c.SelectWithOptions(test.b, test.opts), test.name)
+	}
+}
@@ -0,0 +1,71 @@
+package route
+
+import (
+	"sort"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// LocalAction is a local behavior requested by a community
+type LocalAction uint8
+
+const (
+	// LocalActionSetLocalPrefZero sets the LOCAL_PREF to 0, e.g. for GRACEFUL_SHUTDOWN (RFC8326)
+	LocalActionSetLocalPrefZero LocalAction = iota + 1
+	// LocalActionDiscard discards traffic towards the prefix, e.g. for BLACKHOLE (RFC7999)
+	LocalActionDiscard
+	// LocalActionNoAdvertise stops the path from being advertised to any peer (RFC1997)
+	LocalActionNoAdvertise
+)
+
+// String returns the name of the local action
+func (a LocalAction) String() string {
+	switch a {
+	case LocalActionSetLocalPrefZero:
+		return "set-localpref-0"
+	case LocalActionDiscard:
+		return "discard"
+	case LocalActionNoAdvertise:
+		return "no-advertise"
+	}
+
+	return "unknown"
+}
+
+// DefaultLocalActions maps the well known communities to the local actions they request
+func DefaultLocalActions() map[uint32]LocalAction {
+	return map[uint32]LocalAction{
+		types.WellKnownCommunityGracefulShutdown: LocalActionSetLocalPrefZero,
+		types.WellKnownCommunityBlackhole:        LocalActionDiscard,
+		types.WellKnownCommunityNoAdvertise:      LocalActionNoAdvertise,
+	}
+}
+
+// LocalActions returns the sorted and deduplicated local actions triggered by the communities of b according to table
+func (b *BGPPath) LocalActions(table map[uint32]LocalAction) []LocalAction {
+	ret := make([]LocalAction, 0)
+	if b.Communities == nil {
+		return ret
+	}
+
+	seen := make(map[LocalAction]struct{})
+	for _, com := range *b.Communities {
+		a, ok := table[com]
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[a]; ok {
+			continue
+		}
+
+		seen[a] = struct{}{}
+		ret = append(ret, a)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i] < ret[j]
+	})
+
+	return ret
+}
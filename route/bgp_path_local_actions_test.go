@@ -0,0 +1,54 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalActions(t *testing.T) {
+	tests := []struct {
+		name        string
+		communities *types.Communities
+		expected    []LocalAction
+	}{
+		{
+			name:     "No communities",
+			expected: []LocalAction{},
+		},
+		{
+			name:        "No action",
+			communities: &types.Communities{65000<<16 | 100},
+			expected:    []LocalAction{},
+		},
+		{
+			name: "Graceful shutdown and no advertise",
+			communities: &types.Communities{
+				types.WellKnownCommunityNoAdvertise,
+				65000<<16 | 100,
+				types.WellKnownCommunityGracefulShutdown,
+				types.WellKnownCommunityNoAdvertise,
+			},
+			expected: []LocalAction{
+				LocalActionSetLocalPrefZero,
+				LocalActionNoAdvertise,
+			},
+		},
+		{
+			name:        "Blackhole",
+			communities: &types.Communities{65535<<16 | 666},
+			expected: []LocalAction{
+				LocalActionDiscard,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			Communities: test.communities,
+		}
+
+		assert.Equal(t, test.expected, p.LocalActions(DefaultLocalActions()), test.name)
+	}
+}
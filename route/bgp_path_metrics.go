@@ -0,0 +1,53 @@
+package route
+
+import (
+	"strconv"
+)
+
+// Label names used by MetricLabels
+const (
+	MetricLabelOrigin       = "origin"
+	MetricLabelNeighborAS   = "neighbor_as"
+	MetricLabelOriginAS     = "origin_as"
+	MetricLabelASPathLength = "as_path_length"
+)
+
+// MetricLabels gets label safe values describing the path for per path metrics.
+// The AS path length is bucketed to limit the cardinality.
+func (b *BGPPath) MetricLabels() map[string]string {
+	origin := "incomplete"
+	switch b.BGPPathA.Origin {
+	case 0:
+		origin = "igp"
+	case 1:
+		origin = "egp"
+	}
+
+	neighborAS := "none"
+	if asn, ok := b.NeighborAS(); ok {
+		neighborAS = strconv.FormatUint(uint64(asn), 10)
+	}
+
+	originAS := "none"
+	if asn, ok := b.OriginAS(); ok {
+		originAS = strconv.FormatUint(uint64(asn), 10)
+	}
+
+	return map[string]string{
+		MetricLabelOrigin:       origin,
+		MetricLabelNeighborAS:   neighborAS,
+		MetricLabelOriginAS:     originAS,
+		MetricLabelASPathLength: asPathLengthBucket(b.ASPathLen),
+	}
+}
+
+func asPathLengthBucket(l uint16) string {
+	switch {
+	case l <= 5:
+		return strconv.Itoa(int(l))
+	case l <= 10:
+		return "6_10"
+	}
+
+	return "gt_10"
+}
@@ -0,0 +1,57 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected map[string]string
+	}{
+		{
+			name: "Representative path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					Origin: 0,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 1299, 6939, 13335, 174, 3356, 201701},
+					},
+				},
+				ASPathLen: 7,
+			},
+			expected: map[string]string{
+				"origin":         "igp",
+				"neighbor_as":    "3320",
+				"origin_as":      "201701",
+				"as_path_length": "6_10",
+			},
+		},
+		{
+			name: "Locally originated",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					Origin: 2,
+				},
+				ASPath: &types.ASPath{},
+			},
+			expected: map[string]string{
+				"origin":         "incomplete",
+				"neighbor_as":    "none",
+				"origin_as":      "none",
+				"as_path_length": "0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.MetricLabels(), test.name)
+	}
+}
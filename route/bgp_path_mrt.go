@@ -0,0 +1,530 @@
+package route
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bio-routing/tflow2/convert"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/bio-routing/bio-rd/util/decode"
+)
+
+const (
+	// MRTTypeTableDumpV2 is the MRT type of TABLE_DUMP_V2 records (RFC6396)
+	MRTTypeTableDumpV2 = 13
+
+	// MRTSubTypeRIBIPv4Unicast is the TABLE_DUMP_V2 subtype for IPv4 unicast RIB entries
+	MRTSubTypeRIBIPv4Unicast = 2
+
+	// MRTSubTypeRIBIPv6Unicast is the TABLE_DUMP_V2 subtype for IPv6 unicast RIB entries
+	MRTSubTypeRIBIPv6Unicast = 4
+
+	mrtHeaderLen = 12
+)
+
+// ToMRTRIBEntry encodes the path as an MRT TABLE_DUMP_V2 RIB_IPV4_UNICAST/RIB_IPV6_UNICAST record (RFC6396)
+// with sequence number seq for prefix pfx containing a single RIB entry for the peer with index peerIndex.
+// A path does not know its prefix, so pfx has to be passed by the caller. The record is timestamped with
+// dumpTime, the originated time of the RIB entry is the time the path was received (dumpTime if unknown).
+func (b *BGPPath) ToMRTRIBEntry(peerIndex uint16, seq uint32, pfx *bnet.Prefix, dumpTime time.Time) ([]byte, error) {
+	if pfx == nil {
+		return nil, fmt.Errorf("prefix must not be nil")
+	}
+
+	subType := uint16(MRTSubTypeRIBIPv4Unicast)
+	if !pfx.Addr().IsIPv4() {
+		subType = MRTSubTypeRIBIPv6Unicast
+	}
+
+	attrs := bytes.NewBuffer(nil)
+	err := b.serializeMRTAttributes(attrs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize path attributes: %w", err)
+	}
+
+	if attrs.Len() > math.MaxUint16 {
+		return nil, fmt.Errorf("path attributes too long: %d bytes", attrs.Len())
+	}
+
+	originated := b.ReceivedAt
+	if originated.IsZero() {
+		originated = dumpTime
+	}
+
+	body := bytes.NewBuffer(nil)
+	body.Write(convert.Uint32Byte(seq))
+	body.WriteByte(pfx.Pfxlen())
+	body.Write(pfx.Addr().Bytes()[:bytesForPfxlen(pfx.Pfxlen())])
+	body.Write(convert.Uint16Byte(1))
+	body.Write(convert.Uint16Byte(peerIndex))
+	body.Write(convert.Uint32Byte(uint32(originated.Unix())))
+	body.Write(convert.Uint16Byte(uint16(attrs.Len())))
+	body.Write(attrs.Bytes())
+
+	buf := bytes.NewBuffer(make([]byte, 0, mrtHeaderLen+body.Len()))
+	buf.Write(convert.Uint32Byte(uint32(dumpTime.Unix())))
+	buf.Write(convert.Uint16Byte(MRTTypeTableDumpV2))
+	buf.Write(convert.Uint16Byte(subType))
+	buf.Write(convert.Uint32Byte(uint32(body.Len())))
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func bytesForPfxlen(pfxlen uint8) int {
+	return (int(pfxlen) + 7) / 8
+}
+
+// serializeMRTAttributes serializes the paths attributes. As required by RFC6396 4.3.4 ASNs are always
+// encoded as 4 octets and IPv6 next hops are stored in an abbreviated MP_REACH_NLRI attribute.
+func (b *BGPPath) serializeMRTAttributes(buf *bytes.Buffer) error {
+	writeAttr(buf, types.AttrFlagTransitive, types.OriginAttr, []byte{b.BGPPathA.Origin})
+
+	asPath := bytes.NewBuffer(nil)
+	if b.ASPath != nil {
+		for _, segment := range *b.ASPath {
+			if len(segment.ASNs) > types.MaxASNsSegment {
+				return fmt.Errorf("AS path segment exceeds %d ASNs", types.MaxASNsSegment)
+			}
+
+			asPath.WriteByte(segment.Type)
+			asPath.WriteByte(uint8(len(segment.ASNs)))
+			for _, asn := range segment.ASNs {
+				asPath.Write(convert.Uint32Byte(asn))
+			}
+		}
+	}
+	writeAttr(buf, types.AttrFlagTransitive, types.ASPathAttr, asPath.Bytes())
+
+	if b.BGPPathA.NextHop != nil {
+		if b.BGPPathA.NextHop.IsIPv4() && !b.isIPv6Family() {
+			writeAttr(buf, types.AttrFlagTransitive, types.NextHopAttr, b.BGPPathA.NextHop.Bytes())
+		} else {
+			nh := b.BGPPathA.NextHop.Bytes()
+			if b.BGPPathA.NextHop.IsIPv4() {
+				// IPv4-mapped IPv6 address (RFC4291 2.5.5.2)
+				nh = append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff}, nh...)
+			}
+
+			if b.BGPPathA.NextHopLinkLocal != nil {
+				nh = append(nh, b.BGPPathA.NextHopLinkLocal.Bytes()...)
+			}
+			writeAttr(buf, types.AttrFlagOptional, types.MPReachNLRIAttr, append([]byte{uint8(len(nh))}, nh...))
+		}
+	}
+
+	if b.BGPPathA.MEDPresent() {
+		writeAttr(buf, types.AttrFlagOptional, types.MEDAttr, convert.Uint32Byte(b.BGPPathA.MED))
+	}
+
+	writeAttr(buf, types.AttrFlagTransitive, types.LocalPrefAttr, convert.Uint32Byte(b.BGPPathA.LocalPref))
+
+	if b.BGPPathA.AtomicAggregate {
+		writeAttr(buf, types.AttrFlagTransitive, types.AtomicAggrAttr, nil)
+	}
+
+	if b.BGPPathA.Aggregator != nil {
+		aggr := append(convert.Uint32Byte(uint32(b.BGPPathA.Aggregator.ASN)), convert.Uint32Byte(b.BGPPathA.Aggregator.Address)...)
+		writeAttr(buf, types.AttrFlagOptional|types.AttrFlagTransitive, types.AggregatorAttr, aggr)
+	}
+
+	if b.Communities != nil && len(*b.Communities) > 0 {
+		coms := make([]byte, 0, len(*b.Communities)*4)
+		for _, com := range *b.Communities {
+			coms = append(coms, convert.Uint32Byte(com)...)
+		}
+		writeAttr(buf, types.AttrFlagOptional|types.AttrFlagTransitive, types.CommunitiesAttr, coms)
+	}
+
+	if b.BGPPathA.OriginatorID != 0 {
+		writeAttr(buf, types.AttrFlagOptional, types.OriginatorIDAttr, convert.Uint32Byte(b.BGPPathA.OriginatorID))
+	}
+
+	if b.ClusterList != nil && len(*b.ClusterList) > 0 {
+		cl := make([]byte, 0, len(*b.ClusterList)*4)
+		for _, cid := range *b.ClusterList {
+			cl = append(cl, convert.Uint32Byte(cid)...)
+		}
+		writeAttr(buf, types.AttrFlagOptional, types.ClusterListAttr, cl)
+	}
+
+	if b.LargeCommunities != nil && len(*b.LargeCommunities) > 0 {
+		coms := make([]byte, 0, len(*b.LargeCommunities)*12)
+		for _, com := range *b.LargeCommunities {
+			coms = append(coms, convert.Uint32Byte(com.GlobalAdministrator)...)
+			coms = append(coms, convert.Uint32Byte(com.DataPart1)...)
+			coms = append(coms, convert.Uint32Byte(com.DataPart2)...)
+		}
+		writeAttr(buf, types.AttrFlagOptional|types.AttrFlagTransitive, types.LargeCommunitiesAttr, coms)
+	}
+
+	if b.ExtendedCommunities != nil && len(*b.ExtendedCommunities) > 0 {
+		coms := make([]byte, 0, len(*b.ExtendedCommunities)*types.ExtendedCommunityLen)
+		for i := range *b.ExtendedCommunities {
+			coms = append(coms, (*b.ExtendedCommunities)[i].Bytes()...)
+		}
+		writeAttr(buf, types.AttrFlagOptional|types.AttrFlagTransitive, types.ExtendedCommunitiesAttr, coms)
+	}
+
+	if b.PMSITunnel != nil {
+		writeAttr(buf, types.AttrFlagOptional|types.AttrFlagTransitive, types.PMSITunnelAttr, b.PMSITunnel.Serialize())
+	}
+
+	if b.BGPPathA.AIGPPresent {
+		writeAttr(buf, types.AttrFlagOptional, types.AIGPAttr, types.SerializeAIGP(b.BGPPathA.AIGP))
+	}
+
+	if b.BGPLSAttribute != nil {
+		writeAttr(buf, types.AttrFlagOptional, types.BGPLSAttr, b.BGPLSAttribute.Value)
+	}
+
+	for _, u := range b.UnknownAttributes {
+		flags := uint8(0)
+		if u.Optional {
+			flags |= types.AttrFlagOptional
+		}
+		if u.Transitive {
+			flags |= types.AttrFlagTransitive
+		}
+		if u.Partial {
+			flags |= types.AttrFlagPartial
+		}
+		writeAttr(buf, flags, u.TypeCode, u.Value)
+	}
+
+	return nil
+}
+
+// writeAttr writes a path attribute to buf setting the extended length flag if required
+func writeAttr(buf *bytes.Buffer, flags uint8, typeCode uint8, value []byte) {
+	if len(value) > math.MaxUint8 {
+		buf.WriteByte(flags | types.AttrFlagExtendedLength)
+		buf.WriteByte(typeCode)
+		buf.Write(convert.Uint16Byte(uint16(len(value))))
+	} else {
+		buf.WriteByte(flags &^ types.AttrFlagExtendedLength)
+		buf.WriteByte(typeCode)
+		buf.WriteByte(uint8(len(value)))
+	}
+
+	buf.Write(value)
+}
+
+// BGPPathFromMRTRIBEntry parses an MRT TABLE_DUMP_V2 RIB_IPV4_UNICAST/RIB_IPV6_UNICAST record (RFC6396).
+// Only the first RIB entry of the record is converted. Attributes not known to BIO are kept as unknown attributes.
+func BGPPathFromMRTRIBEntry(data []byte) (*BGPPath, bnet.Prefix, error) {
+	buf := bytes.NewBuffer(data)
+
+	var ts, length uint32
+	var mrtType, subType uint16
+	err := decode.Decode(buf, []interface{}{&ts, &mrtType, &subType, &length})
+	if err != nil {
+		return nil, bnet.Prefix{}, fmt.Errorf("unable to decode MRT header: %w", err)
+	}
+
+	if mrtType != MRTTypeTableDumpV2 {
+		return nil, bnet.Prefix{}, fmt.Errorf("unexpected MRT type %d", mrtType)
+	}
+
+	addrLen := 0
+	afi := uint16(0)
+	switch subType {
+	case MRTSubTypeRIBIPv4Unicast:
+		addrLen = 4
+		afi = afiIPv4
+	case MRTSubTypeRIBIPv6Unicast:
+		addrLen = 16
+		afi = afiIPv6
+	default:
+		return nil, bnet.Prefix{}, fmt.Errorf("unsupported TABLE_DUMP_V2 subtype %d", subType)
+	}
+
+	if int(length) > buf.Len() {
+		return nil, bnet.Prefix{}, fmt.Errorf("MRT record truncated: expected %d bytes, got %d", length, buf.Len())
+	}
+
+	// Everything below is bounded to the length declared in the MRT header
+	buf = bytes.NewBuffer(buf.Next(int(length)))
+
+	var seq uint32
+	var pfxlen uint8
+	err = decode.Decode(buf, []interface{}{&seq, &pfxlen})
+	if err != nil {
+		return nil, bnet.Prefix{}, fmt.Errorf("unable to decode RIB header: %w", err)
+	}
+
+	if int(pfxlen) > addrLen*8 {
+		return nil, bnet.Prefix{}, fmt.Errorf("invalid prefix length %d", pfxlen)
+	}
+
+	addr := make([]byte, addrLen)
+	n := bytesForPfxlen(pfxlen)
+	if buf.Len() < n {
+		return nil, bnet.Prefix{}, fmt.Errorf("prefix truncated")
+	}
+	copy(addr, buf.Next(n))
+
+	ip, err := bnet.IPFromBytes(addr)
+	if err != nil {
+		return nil, bnet.Prefix{}, err
+	}
+	pfx := bnet.NewPfx(ip, pfxlen)
+
+	var entryCount, peerIndex, attrLen uint16
+	var originated uint32
+	err = decode.Decode(buf, []interface{}{&entryCount, &peerIndex, &originated, &attrLen})
+	if err != nil {
+		return nil, bnet.Prefix{}, fmt.Errorf("unable to decode RIB entry: %w", err)
+	}
+
+	if entryCount == 0 {
+		return nil, bnet.Prefix{}, fmt.Errorf("RIB record contains no entries")
+	}
+
+	if int(attrLen) > buf.Len() {
+		return nil, bnet.Prefix{}, fmt.Errorf("path attributes truncated: expected %d bytes, got %d", attrLen, buf.Len())
+	}
+
+	p := &BGPPath{
+		BGPPathA: NewBGPPathA(),
+		ASPath:   &types.ASPath{},
+		AFI:      afi,
+		SAFI:     safiUnicast,
+	}
+
+	err = p.decodeMRTAttributes(bytes.NewBuffer(buf.Next(int(attrLen))))
+	if err != nil {
+		return nil, bnet.Prefix{}, fmt.Errorf("unable to decode path attributes: %w", err)
+	}
+
+	p.ASPathLen = p.ASPath.Length()
+	return p, pfx, nil
+}
+
+func (b *BGPPath) decodeMRTAttributes(buf *bytes.Buffer) error {
+	for buf.Len() > 0 {
+		var flags, typeCode uint8
+		err := decode.Decode(buf, []interface{}{&flags, &typeCode})
+		if err != nil {
+			return err
+		}
+
+		length := uint16(0)
+		if flags&types.AttrFlagExtendedLength != 0 {
+			err = decode.DecodeUint16(buf, &length)
+		} else {
+			var l uint8
+			err = decode.DecodeUint8(buf, &l)
+			length = uint16(l)
+		}
+		if err != nil {
+			return err
+		}
+
+		if int(length) > buf.Len() {
+			return fmt.Errorf("attribute %d truncated", typeCode)
+		}
+		value := buf.Next(int(length))
+
+		err = b.decodeMRTAttribute(flags, typeCode, value)
+		if err != nil {
+			return fmt.Errorf("unable to decode attribute %d: %w", typeCode, err)
+		}
+	}
+
+	return nil
+}
+
+func (b *BGPPath) decodeMRTAttribute(flags uint8, typeCode uint8, value []byte) error {
+	switch typeCode {
+	case types.OriginAttr:
+		if len(value) != 1 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+		b.BGPPathA.Origin = value[0]
+	case types.ASPathAttr:
+		return b.decodeMRTASPath(value)
+	case types.NextHopAttr:
+		ip, err := bnet.IPFromBytes(value)
+		if err != nil {
+			return err
+		}
+		b.BGPPathA.NextHop = ip.Dedup()
+	case types.MPReachNLRIAttr:
+		if len(value) < 1 || int(value[0])+1 > len(value) {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+
+		nhLen := int(value[0])
+		if nhLen == 32 {
+			ll, err := bnet.IPFromBytes(value[17:33])
+			if err != nil {
+				return err
+			}
+			b.BGPPathA.NextHopLinkLocal = ll.Dedup()
+			nhLen = 16
+		}
+
+		ip, err := bnet.IPFromBytes(value[1 : 1+nhLen])
+		if err != nil {
+			return err
+		}
+		b.BGPPathA.NextHop = ip.Dedup()
+	case types.MEDAttr:
+		if len(value) != 4 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+		b.BGPPathA.MED = convert.Uint32b(value)
+		b.BGPPathA.HasMED = true
+	case types.LocalPrefAttr:
+		if len(value) != 4 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+		b.BGPPathA.LocalPref = convert.Uint32b(value)
+	case types.AtomicAggrAttr:
+		b.BGPPathA.AtomicAggregate = true
+	case types.AggregatorAttr:
+		switch len(value) {
+		case 6:
+			b.BGPPathA.Aggregator = &types.Aggregator{
+				ASN:     convert.Uint16b(value[0:2]),
+				Address: convert.Uint32b(value[2:6]),
+			}
+		case 8:
+			// Aggregator only holds 2 octet ASNs, 4 octet ASNs are replaced by AS_TRANS (RFC6793)
+			asn := convert.Uint32b(value[0:4])
+			if asn > math.MaxUint16 {
+				asn = types.ASTransASN
+			}
+
+			b.BGPPathA.Aggregator = &types.Aggregator{
+				ASN:     uint16(asn),
+				Address: convert.Uint32b(value[4:8]),
+			}
+		default:
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+	case types.CommunitiesAttr:
+		if len(value)%4 != 0 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+
+		coms := make(types.Communities, len(value)/4)
+		for i := range coms {
+			coms[i] = convert.Uint32b(value[i*4 : i*4+4])
+		}
+		b.Communities = &coms
+	case types.OriginatorIDAttr:
+		if len(value) != 4 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+		b.BGPPathA.OriginatorID = convert.Uint32b(value)
+	case types.ClusterListAttr:
+		if len(value)%4 != 0 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+
+		cl := make(types.ClusterList, len(value)/4)
+		for i := range cl {
+			cl[i] = convert.Uint32b(value[i*4 : i*4+4])
+		}
+		b.ClusterList = &cl
+	case types.LargeCommunitiesAttr:
+		if len(value)%12 != 0 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+
+		coms := make(types.LargeCommunities, len(value)/12)
+		for i := range coms {
+			coms[i] = types.LargeCommunity{
+				GlobalAdministrator: convert.Uint32b(value[i*12 : i*12+4]),
+				DataPart1:           convert.Uint32b(value[i*12+4 : i*12+8]),
+				DataPart2:           convert.Uint32b(value[i*12+8 : i*12+12]),
+			}
+		}
+		b.LargeCommunities = &coms
+	case types.ExtendedCommunitiesAttr:
+		if len(value)%types.ExtendedCommunityLen != 0 {
+			return fmt.Errorf("invalid length %d", len(value))
+		}
+
+		coms := make([]types.ExtendedCommunity, len(value)/types.ExtendedCommunityLen)
+		for i := range coms {
+			coms[i], _ = types.ExtendedCommunityFromBytes(value[i*types.ExtendedCommunityLen : (i+1)*types.ExtendedCommunityLen])
+		}
+		b.ExtendedCommunities = &coms
+	case types.PMSITunnelAttr:
+		t, err := types.DecodePMSITunnel(value)
+		if err != nil {
+			return err
+		}
+		b.PMSITunnel = t
+	case types.AIGPAttr:
+		metric, found, err := types.DecodeAIGP(value)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			b.BGPPathA.AIGP = metric
+			b.BGPPathA.AIGPPresent = true
+		}
+	case types.BGPLSAttr:
+		v := make([]byte, len(value))
+		copy(v, value)
+
+		b.BGPLSAttribute = &types.BGPLSAttribute{
+			Value: v,
+		}
+	default:
+		v := make([]byte, len(value))
+		copy(v, value)
+
+		b.UnknownAttributes = append(b.UnknownAttributes, types.UnknownPathAttribute{
+			Optional:   flags&types.AttrFlagOptional != 0,
+			Transitive: flags&types.AttrFlagTransitive != 0,
+			Partial:    flags&types.AttrFlagPartial != 0,
+			TypeCode:   typeCode,
+			Value:      v,
+		})
+	}
+
+	return nil
+}
+
+func (b *BGPPath) decodeMRTASPath(value []byte) error {
+	asPath := make(types.ASPath, 0, 1)
+	for p := 0; p < len(value); {
+		if p+2 > len(value) {
+			return fmt.Errorf("AS path segment header truncated")
+		}
+
+		segment := types.ASPathSegment{
+			Type: value[p],
+			ASNs: make([]uint32, value[p+1]),
+		}
+		p += 2
+
+		if segment.Type < types.ASSet || segment.Type > types.ASConfedSet {
+			return fmt.Errorf("invalid AS path segment type: %d", segment.Type)
+		}
+
+		if p+4*len(segment.ASNs) > len(value) {
+			return fmt.Errorf("AS path segment truncated")
+		}
+
+		for i := range segment.ASNs {
+			segment.ASNs[i] = convert.Uint32b(value[p : p+4])
+			p += 4
+		}
+
+		asPath = append(asPath, segment)
+	}
+
+	b.ASPath = &asPath
+	return nil
+}
@@ -0,0 +1,403 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMRTRIBEntry(t *testing.T) {
+	dumpTime := time.Unix(1600000000, 0)
+
+	tests := []struct {
+		name      string
+		pfx       *bnet.Prefix
+		peerIndex uint16
+		seq       uint32
+		path      *BGPPath
+		expected  []byte
+		wantFail  bool
+	}{
+		{
+			name:      "IPv4",
+			pfx:       bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24).Ptr(),
+			peerIndex: 3,
+			seq:       42,
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					Source:    bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+					MED:       5,
+					Origin:    0,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{65001, 201701},
+					},
+				},
+				Communities: &types.Communities{0xFDE90064},
+				ReceivedAt:  time.Unix(1599999000, 0),
+			},
+			expected: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 2, // Subtype: RIB_IPV4_UNICAST
+				0, 0, 0, 63, // Length
+				0, 0, 0, 42, // Sequence number
+				24, 192, 0, 2, // Prefix
+				0, 1, // Entry count
+				0, 3, // Peer index
+				0x5F, 0x5E, 0x0C, 0x18, // Originated time
+				0, 45, // Attribute length
+				64, 1, 1, 0, // Origin
+				64, 2, 10, 2, 2, 0, 0, 0xFD, 0xE9, 0, 3, 0x13, 0xE5, // AS Path
+				64, 3, 4, 10, 0, 0, 1, // Next Hop
+				128, 4, 4, 0, 0, 0, 5, // MED
+				64, 5, 4, 0, 0, 0, 100, // Local Pref
+				192, 8, 4, 0xFD, 0xE9, 0, 0x64, // Communities
+			},
+		},
+		{
+			name:      "IPv6",
+			pfx:       bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32).Ptr(),
+			peerIndex: 0,
+			seq:       1,
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+					Source:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+					Origin:  2,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{65001},
+					},
+				},
+			},
+			expected: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 4, // Subtype: RIB_IPV6_UNICAST
+				0, 0, 0, 59, // Length
+				0, 0, 0, 1, // Sequence number
+				32, 0x20, 0x01, 0x0d, 0xb8, // Prefix
+				0, 1, // Entry count
+				0, 0, // Peer index
+				0x5F, 0x5E, 0x10, 0x00, // Originated time
+				0, 40, // Attribute length
+				64, 1, 1, 2, // Origin
+				64, 2, 6, 2, 1, 0, 0, 0xFD, 0xE9, // AS Path
+				128, 14, 17, 16, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, // MP_REACH_NLRI
+				64, 5, 4, 0, 0, 0, 0, // Local Pref
+			},
+		},
+		{
+			name:     "No prefix",
+			path:     &BGPPath{BGPPathA: NewBGPPathA()},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		res, err := test.path.ToMRTRIBEntry(test.peerIndex, test.seq, test.pfx, dumpTime)
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, res, test.name)
+	}
+}
+
+func TestBGPPathFromMRTRIBEntry(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []byte
+		expected    *BGPPath
+		expectedPfx bnet.Prefix
+		wantFail    bool
+	}{
+		{
+			name: "IPv4 with unknown attribute",
+			input: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 2, // Subtype: RIB_IPV4_UNICAST
+				0, 0, 0, 69, // Length
+				0, 0, 0, 7, // Sequence number
+				22, 198, 51, 100, // Prefix
+				0, 1, // Entry count
+				0, 1, // Peer index
+				0x5F, 0x5E, 0x10, 0x00, // Originated time
+				0, 51, // Attribute length
+				64, 1, 1, 0, // Origin
+				64, 2, 14, 2, 3, 0, 0, 0x0C, 0xF8, 0, 0, 0xFD, 0xE9, 0, 0, 0xFD, 0xEA, // AS Path
+				64, 3, 4, 192, 0, 2, 1, // Next Hop
+				64, 5, 4, 0, 0, 0, 100, // Local Pref
+				192, 8, 8, 0xFD, 0xE9, 0, 0x64, 0xFF, 0xFF, 0xFF, 0x01, // Communities
+				192, 250, 2, 0xAB, 0xCD, // Unknown attribute
+			},
+			expected: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(192, 0, 2, 1).Ptr(),
+					Source:    bnet.IPv4(0).Ptr(),
+					LocalPref: 100,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 65001, 65002},
+					},
+				},
+				ASPathLen:   3,
+				AFI:         afiIPv4,
+				SAFI:        safiUnicast,
+				Communities: &types.Communities{0xFDE90064, types.WellKnownCommunityNoExport},
+				UnknownAttributes: []types.UnknownPathAttribute{
+					{
+						Optional:   true,
+						Transitive: true,
+						TypeCode:   250,
+						Value:      []byte{0xAB, 0xCD},
+					},
+				},
+			},
+			expectedPfx: bnet.NewPfx(bnet.IPv4FromOctets(198, 51, 100, 0), 22),
+		},
+		{
+			name: "Wrong MRT type",
+			input: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 12, // Type: TABLE_DUMP
+				0, 1, // Subtype
+				0, 0, 0, 0, // Length
+			},
+			wantFail: true,
+		},
+		{
+			name: "Truncated",
+			input: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 2, // Subtype: RIB_IPV4_UNICAST
+				0, 0, 0, 70, // Length
+				0, 0, 0, 7, // Sequence number
+			},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		p, pfx, err := BGPPathFromMRTRIBEntry(test.input)
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, p, test.name)
+		assert.Equal(t, test.expectedPfx, pfx, test.name)
+	}
+}
+
+func TestBGPPathFromMRTRIBEntryAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          []byte
+		expectedASPath *types.ASPath
+		wantFail       bool
+	}{
+		{
+			name: "Confed segments",
+			input: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 2, // Subtype: RIB_IPV4_UNICAST
+				0, 0, 0, 37, // Length
+				0, 0, 0, 7, // Sequence number
+				24, 192, 0, 2, // Prefix
+				0, 1, // Entry count
+				0, 1, // Peer index
+				0x5F, 0x5E, 0x10, 0x00, // Originated time
+				0, 19, // Attribute length
+				64, 1, 1, 0, // Origin
+				64, 2, 12, 3, 1, 0, 0, 0xFD, 0xE8, 2, 1, 0, 0, 0x0C, 0xF8, // AS Path
+			},
+			expectedASPath: &types.ASPath{
+				{
+					Type: types.ASConfedSequence,
+					ASNs: []uint32{65000},
+				},
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{3320},
+				},
+			},
+		},
+		{
+			name: "Attributes exceed record length",
+			input: []byte{
+				0x5F, 0x5E, 0x10, 0x00, // Timestamp
+				0, 13, // Type: TABLE_DUMP_V2
+				0, 2, // Subtype: RIB_IPV4_UNICAST
+				0, 0, 0, 22, // Length
+				0, 0, 0, 7, // Sequence number
+				24, 192, 0, 2, // Prefix
+				0, 1, // Entry count
+				0, 1, // Peer index
+				0x5F, 0x5E, 0x10, 0x00, // Originated time
+				0, 8, // Attribute length
+				64, 1, 1, 0, // Origin
+				64, 1, 1, 0, // Beyond the record
+			},
+			wantFail: true,
+		},
+	}
+
+	for _, test := range tests {
+		p, _, err := BGPPathFromMRTRIBEntry(test.input)
+		if test.wantFail {
+			assert.Error(t, err, test.name)
+			continue
+		}
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expectedASPath, p.ASPath, test.name)
+		assert.Equal(t, uint16(1), p.ASPathLen, test.name)
+	}
+}
+
+func TestBGPPathFromMRTRIBEntry4OctetAggregator(t *testing.T) {
+	input := []byte{
+		0x5F, 0x5E, 0x10, 0x00, // Timestamp
+		0, 13, // Type: TABLE_DUMP_V2
+		0, 2, // Subtype: RIB_IPV4_UNICAST
+		0, 0, 0, 33, // Length
+		0, 0, 0, 7, // Sequence number
+		24, 192, 0, 2, // Prefix
+		0, 1, // Entry count
+		0, 1, // Peer index
+		0x5F, 0x5E, 0x10, 0x00, // Originated time
+		0, 15, // Attribute length
+		64, 1, 1, 0, // Origin
+		192, 7, 8, 0xFA, 0x56, 0xEA, 0x00, 10, 0, 0, 1, // Aggregator (AS4200000000)
+	}
+
+	p, _, err := BGPPathFromMRTRIBEntry(input)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Aggregator{
+		ASN:     types.ASTransASN,
+		Address: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr().ToUint32(),
+	}, p.BGPPathA.Aggregator)
+}
+
+func TestMRTRIBEntryRoundTrip(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:          bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			Source:           bnet.IPv4(0).Ptr(),
+			LocalPref:        200,
+			MED:              10,
+			HasMED:           true,
+			Origin:           1,
+			OriginatorID:     42,
+			AtomicAggregate:  true,
+			Aggregator: &types.Aggregator{
+				ASN:     65001,
+				Address: 100,
+			},
+			AIGP:        500,
+			AIGPPresent: true,
+		},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{65001, 201701},
+			},
+			{
+				Type: types.ASSet,
+				ASNs: []uint32{1, 2},
+			},
+		},
+		ASPathLen:   3,
+		AFI:         afiIPv6,
+		SAFI:        safiUnicast,
+		Communities: &types.Communities{100},
+		LargeCommunities: &types.LargeCommunities{
+			{
+				GlobalAdministrator: 201701,
+				DataPart1:           1,
+				DataPart2:           2,
+			},
+		},
+		ClusterList: &types.ClusterList{1, 2},
+		PMSITunnel: &types.PMSITunnel{
+			TunnelType:       types.PMSITunnelTypeIngressReplication,
+			Label:            10100,
+			TunnelIdentifier: []byte{192, 0, 2, 1},
+		},
+		BGPLSAttribute: &types.BGPLSAttribute{
+			Value: []byte{0x04, 0x02, 0x00, 0x02, 'r', '1'},
+		},
+	}
+	pfx := bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0x100, 0, 0, 0, 0, 0), 40)
+
+	b, err := p.ToMRTRIBEntry(0, 0, &pfx, time.Time{})
+	assert.NoError(t, err)
+
+	res, resPfx, err := BGPPathFromMRTRIBEntry(b)
+	assert.NoError(t, err)
+	assert.Equal(t, p, res)
+	assert.Equal(t, pfx, resPfx)
+}
+
+func TestToMRTRIBEntryMappedNextHop(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+	}
+	pfx := bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32)
+
+	tests := []struct {
+		name     string
+		afi      uint16
+		expected []byte
+	}{
+		{
+			name:     "Family inferred from next hop",
+			expected: []byte{64, 3, 4, 10, 0, 0, 1},
+		},
+		{
+			name: "IPv6 family",
+			afi:  afiIPv6,
+			expected: []byte{
+				128, 14, 17, 16, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10, 0, 0, 1, // MP_REACH_NLRI
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p.AFI = test.afi
+
+		b, err := p.ToMRTRIBEntry(0, 0, &pfx, time.Time{})
+		assert.NoError(t, err, test.name)
+		assert.Contains(t, string(b), string(test.expected), test.name)
+
+		res, _, err := BGPPathFromMRTRIBEntry(b)
+		assert.NoError(t, err, test.name)
+		if test.afi == afiIPv6 {
+			assert.Equal(t, bnet.IPv6(0, 0xffff0a000001).Ptr(), res.BGPPathA.NextHop, test.name)
+		}
+	}
+}
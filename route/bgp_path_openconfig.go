@@ -0,0 +1,82 @@
+package route
+
+import (
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// OpenConfig AS path segment types (openconfig-bgp-types AS_PATH_SEGMENT_TYPE)
+const (
+	OCASPathSegmentSequence = "AS_SEQ"
+	OCASPathSegmentSet      = "AS_SET"
+)
+
+// OCBGPRIBEntry is a path rendered after the attr-set of the OpenConfig BGP RIB model (openconfig-rib-bgp)
+type OCBGPRIBEntry struct {
+	PathID              uint32
+	Origin              string
+	ASPath              []OCASPathSegment
+	NextHop             string
+	LocalPref           uint32
+	MED                 uint32
+	Communities         []string
+	ExtendedCommunities []string
+}
+
+// OCASPathSegment is an AS path segment of the OpenConfig BGP RIB model
+type OCASPathSegment struct {
+	Type    string
+	Members []uint32
+}
+
+// ToOpenConfigRIBEntry converts the path into the OpenConfig BGP RIB model
+func (b *BGPPath) ToOpenConfigRIBEntry() OCBGPRIBEntry {
+	e := OCBGPRIBEntry{
+		PathID:    b.PathIdentifier,
+		Origin:    ocOrigin(b.BGPPathA.Origin),
+		LocalPref: b.BGPPathA.LocalPref,
+		MED:       b.BGPPathA.MED,
+	}
+
+	if b.BGPPathA.NextHop != nil {
+		e.NextHop = b.BGPPathA.NextHop.String()
+	}
+
+	if b.ASPath != nil {
+		for _, seg := range *b.ASPath {
+			segType := OCASPathSegmentSequence
+			if seg.Type == types.ASSet {
+				segType = OCASPathSegmentSet
+			}
+
+			e.ASPath = append(e.ASPath, OCASPathSegment{
+				Type:    segType,
+				Members: append([]uint32(nil), seg.ASNs...),
+			})
+		}
+	}
+
+	if b.Communities != nil {
+		for _, com := range *b.Communities {
+			e.Communities = append(e.Communities, types.CommunityStringForUint32(com))
+		}
+	}
+
+	if b.ExtendedCommunities != nil {
+		for i := range *b.ExtendedCommunities {
+			e.ExtendedCommunities = append(e.ExtendedCommunities, (*b.ExtendedCommunities)[i].String())
+		}
+	}
+
+	return e
+}
+
+func ocOrigin(origin uint8) string {
+	switch origin {
+	case 0:
+		return "IGP"
+	case 1:
+		return "EGP"
+	}
+
+	return "INCOMPLETE"
+}
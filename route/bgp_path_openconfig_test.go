@@ -0,0 +1,82 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToOpenConfigRIBEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected OCBGPRIBEntry
+	}{
+		{
+			name: "Representative path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 200,
+					MED:       10,
+					Origin:    1,
+				},
+				PathIdentifier: 3,
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{65001, 65002},
+					},
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{65003, 65004},
+					},
+				},
+				Communities: &types.Communities{65001<<16 + 100},
+				ExtendedCommunities: &[]types.ExtendedCommunity{
+					{
+						Type:    types.ExtendedCommunityTypeTwoOctetAS,
+						SubType: types.ExtendedCommunitySubTypeRouteTarget,
+						Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+					},
+				},
+			},
+			expected: OCBGPRIBEntry{
+				PathID:  3,
+				Origin:  "EGP",
+				NextHop: "10.0.0.1",
+				ASPath: []OCASPathSegment{
+					{
+						Type:    OCASPathSegmentSequence,
+						Members: []uint32{65001, 65002},
+					},
+					{
+						Type:    OCASPathSegmentSet,
+						Members: []uint32{65003, 65004},
+					},
+				},
+				LocalPref:           200,
+				MED:                 10,
+				Communities:         []string{"(65001,100)"},
+				ExtendedCommunities: []string{"(rt,65000,1)"},
+			},
+		},
+		{
+			name: "Minimal path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					Origin: 2,
+				},
+			},
+			expected: OCBGPRIBEntry{
+				Origin: "INCOMPLETE",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.ToOpenConfigRIBEntry(), test.name)
+	}
+}
@@ -1,13 +1,16 @@
 package route
 
 import (
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/bio-routing/bio-rd/net"
 	bnet "github.com/bio-routing/bio-rd/net"
 	"github.com/bio-routing/bio-rd/protocols/bgp/types"
 	"github.com/bio-routing/bio-rd/route/api"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestBGPPathFromProtoBGPPath(t *testing.T) {
@@ -56,6 +59,7 @@ func TestBGPPathFromProtoBGPPath(t *testing.T) {
 
 	expected := &BGPPath{
 		PathIdentifier: 100,
+		ASPathLen:      2,
 		BGPPathA: &BGPPathA{
 			BGPIdentifier: 123,
 			Source:        bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
@@ -104,6 +108,32 @@ func TestBGPPathFromProtoBGPPath(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestBGPPathFromProtoBGPPathASPathLen(t *testing.T) {
+	newPath := func(asns ...uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				NextHop:   bnet.IPv4(0).Ptr(),
+				Source:    bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+		}
+	}
+
+	short := BGPPathFromProtoBGPPath(newPath(3320).ToProto(), false)
+	long := BGPPathFromProtoBGPPath(newPath(3320, 6939, 201701).ToProto(), false)
+
+	assert.Equal(t, uint16(1), short.ASPathLen)
+	assert.Equal(t, uint16(3), long.ASPathLen)
+	assert.Equal(t, int8(1), short.Select(long))
+	assert.Equal(t, int8(-1), long.Select(short))
+}
+
 func TestBGPSelect(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -310,6 +340,11 @@ func TestCommunitiesString(t *testing.T) {
 			comms:    types.Communities{131080, 16778241},
 			expected: "(2,8) (256,1025)",
 		},
+		{
+			name:     "blackhole",
+			comms:    types.Communities{131080, 65535<<16 + 666},
+			expected: "(2,8) BLACKHOLE",
+		},
 	}
 
 	for _, test := range tests {
@@ -449,7 +484,24 @@ func TestLength(t *testing.T) {
 				LargeCommunities: &types.LargeCommunities{},
 				Communities:      &types.Communities{},
 			},
-			expected: 44,
+			expected: 45,
+		},
+		{
+			name: "Atomic aggregate",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:         bnet.IPv4(0).Ptr(),
+					Source:          bnet.IPv4(0).Ptr(),
+					AtomicAggregate: true,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{15169, 199714},
+					},
+				},
+			},
+			expected: 48,
 		},
 		{
 			name: "communities",
@@ -464,7 +516,7 @@ func TestLength(t *testing.T) {
 				LargeCommunities: &types.LargeCommunities{},
 				Communities:      &types.Communities{10, 20, 30},
 			},
-			expected: 59,
+			expected: 60,
 		},
 		{
 			name: "large communities",
@@ -489,7 +541,7 @@ func TestLength(t *testing.T) {
 					},
 				},
 			},
-			expected: 71,
+			expected: 72,
 		},
 		{
 			name: "Cluster list, unknown attr and originator",
@@ -513,7 +565,7 @@ func TestLength(t *testing.T) {
 					NextHop:      net.IPv4(0).Ptr(),
 				},
 			},
-			expected: 54,
+			expected: 70,
 		},
 	}
 
@@ -541,7 +593,7 @@ func TestBGPPathString(t *testing.T) {
 				Communities:      &types.Communities{},
 				LargeCommunities: &types.LargeCommunities{},
 			},
-			expectedString: "Local Pref: 0, Origin: IGP, AS Path: , BGP type: external, NEXT HOP: 0:0:0:0:0:0:0:0, MED: 0, Path ID: 0, Source: 0:0:0:0:0:0:0:0, Communities: [], LargeCommunities: [], OriginatorID: 0.0.0.23, ClusterList 0.0.0.10 0.0.0.20",
+			expectedString: "Local Pref: 0, Origin: IGP, AS Path: , BGP type: external, NEXT HOP: 0:0:0:0:0:0:0:0, MED: 0, Path ID: 0, Source: 0:0:0:0:0:0:0:0, Communities: [], LargeCommunities: [], OriginatorID: 0.0.0.23, ClusterList 0.0.0.10 0.0.0.20, Source Protocol: BGP",
 			expectedPrint: `		Local Pref: 0
 		Origin: IGP
 		AS Path: 
@@ -563,3 +615,2824 @@ func TestBGPPathString(t *testing.T) {
 		assert.Equal(t, test.expectedPrint, test.input.Print())
 	}
 }
+
+func TestSameForwarding(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *BGPPath
+		q        *BGPPath
+		expected bool
+	}{
+		{
+			name: "Different attributes, same resolved next hop",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+				},
+				Communities:     &types.Communities{100},
+				ResolvedNextHop: bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+					LocalPref: 200,
+					MED:       10,
+				},
+				ResolvedNextHop: bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			},
+			expected: true,
+		},
+		{
+			name: "Different resolved next hop",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+				ResolvedNextHop: bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+				ResolvedNextHop: bnet.IPv4FromOctets(192, 168, 0, 2).Ptr(),
+			},
+			expected: false,
+		},
+		{
+			name: "Unresolved, same next hop",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+				},
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 300,
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Resolved next hop equals next hop of unresolved path",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+				ResolvedNextHop: bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.p.SameForwarding(test.q), test.name)
+	}
+}
+
+func TestBGPPathShowFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		best     bool
+		expected string
+	}{
+		{
+			name: "Best path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+					MED:       20,
+					Origin:    0,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{65001, 65002},
+					},
+				},
+			},
+			best:     true,
+			expected: "*> 10.0.0.1                20    100      0 65001 65002 i",
+		},
+		{
+			name: "Empty AS path with incomplete origin",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+					LocalPref: 200,
+					Origin:    2,
+				},
+				ASPath: &types.ASPath{},
+			},
+			expected: "*  10.0.0.2                 0    200      0 ?",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.ShowFormat(test.best), test.name)
+	}
+}
+
+func TestBGPPathSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		best     bool
+		expected string
+	}{
+		{
+			name: "Best path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					LocalPref: 100,
+					MED:       20,
+					Origin:    1,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 201701},
+					},
+				},
+			},
+			best:     true,
+			expected: "*> 10.0.0.1 20 100 3320 201701 e",
+		},
+		{
+			name: "Non best path with empty AS path",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+					LocalPref: 200,
+				},
+				ASPath: &types.ASPath{},
+			},
+			expected: "*  10.0.0.2 0 200 i",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.Summary(test.best), test.name)
+	}
+}
+
+func TestSourceProtocol(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+		ASPath:         &types.ASPath{},
+		SourceProtocol: SourceProtocolStatic,
+	}
+
+	cp := p.Copy()
+	assert.Equal(t, SourceProtocolStatic, cp.SourceProtocol)
+	assert.Contains(t, p.String(), "Source Protocol: static")
+
+	cp.SourceProtocol = SourceProtocolOSPF
+	assert.Equal(t, p.ComputeHash(), cp.ComputeHash())
+	assert.Equal(t, p.ComputeHashWithPathID(), cp.ComputeHashWithPathID())
+}
+
+func TestSplitCommunitiesForWire(t *testing.T) {
+	coms := make(types.Communities, 20000)
+	for i := range coms {
+		coms[i] = uint32(i)
+	}
+
+	tests := []struct {
+		name           string
+		path           *BGPPath
+		expectedChunks []int
+	}{
+		{
+			name:           "No communities",
+			path:           &BGPPath{},
+			expectedChunks: []int{},
+		},
+		{
+			name: "Few communities",
+			path: &BGPPath{
+				Communities: &types.Communities{1, 2, 3},
+			},
+			expectedChunks: []int{3},
+		},
+		{
+			name: "20000 communities",
+			path: &BGPPath{
+				Communities: &coms,
+			},
+			expectedChunks: []int{16383, 3617},
+		},
+	}
+
+	for _, test := range tests {
+		res := test.path.SplitCommunitiesForWire()
+
+		lens := make([]int, len(res))
+		for i := range res {
+			lens[i] = len(res[i])
+		}
+		assert.Equal(t, test.expectedChunks, lens, test.name)
+
+		if len(res) == 2 {
+			assert.Equal(t, uint32(16383), res[1][0], test.name)
+		}
+	}
+}
+
+func TestBGPPathADedupLinkLocalNextHop(t *testing.T) {
+	nh := bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Dedup()
+
+	a := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:          nh,
+			NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			Source:           nh,
+			LocalPref:        913,
+		},
+	}
+	b := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:          nh,
+			NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 2).Ptr(),
+			Source:           nh,
+			LocalPref:        913,
+		},
+	}
+	c := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:          nh,
+			NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			Source:           nh,
+			LocalPref:        913,
+		},
+	}
+
+	a = a.Dedup()
+	b = b.Dedup()
+	c = c.Dedup()
+
+	assert.True(t, a.BGPPathA != b.BGPPathA, "paths with different link local next hop must not share BGPPathA")
+	assert.True(t, a.BGPPathA == c.BGPPathA, "paths with same link local next hop must share BGPPathA")
+	assert.False(t, a.Compare(b))
+	assert.True(t, a.Compare(c))
+	assert.Equal(t, int8(0), a.Select(b))
+}
+
+func TestBGPSelectIBGPIgnoreRouterID(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *BGPPath
+		q        *BGPPath
+		opts     BGPSelectOptions
+		expected int8
+	}{
+		{
+			name: "iBGP, router id compared",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 1,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 2,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			expected: -1,
+		},
+		{
+			name: "iBGP, router id ignored",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 1,
+					Source:        bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					NextHop:       bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 2,
+					Source:        bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+					NextHop:       bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				},
+			},
+			opts: BGPSelectOptions{
+				IBGPIgnoreRouterID: true,
+			},
+			expected: 0,
+		},
+		{
+			name: "iBGP, router id ignored, MED still decides",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 1,
+					MED:           20,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					BGPIdentifier: 2,
+					MED:           10,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			opts: BGPSelectOptions{
+				IBGPIgnoreRouterID: true,
+			},
+			expected: -1,
+		},
+		{
+			name: "eBGP, router id still compared",
+			p: &BGPPath{
+				BGPPathA: &BGPPathA{
+					EBGP:          true,
+					BGPIdentifier: 1,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			q: &BGPPath{
+				BGPPathA: &BGPPathA{
+					EBGP:          true,
+					BGPIdentifier: 2,
+					Source:        bnet.IPv4(0).Ptr(),
+					NextHop:       bnet.IPv4(0).Ptr(),
+				},
+			},
+			opts: BGPSelectOptions{
+				IBGPIgnoreRouterID: true,
+			},
+			expected: -1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.p.SelectWithOptions(test.q, test.opts), test.name)
+	}
+}
+
+func TestPreferenceFromCommunities(t *testing.T) {
+	table := map[uint32]int{
+		65000<<16 + 100: 100,
+		65000<<16 + 200: 200,
+		65000<<16 + 50:  -50,
+	}
+
+	tests := []struct {
+		name          string
+		path          *BGPPath
+		expectedPref  int
+		expectedFound bool
+	}{
+		{
+			name: "No communities",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+			},
+		},
+		{
+			name: "No matching community",
+			path: &BGPPath{
+				BGPPathA:    &BGPPathA{},
+				Communities: &types.Communities{65000<<16 + 1},
+			},
+		},
+		{
+			name: "Multiple matching communities, highest wins",
+			path: &BGPPath{
+				BGPPathA:    &BGPPathA{},
+				Communities: &types.Communities{65000<<16 + 100, 65000<<16 + 1, 65000<<16 + 200},
+			},
+			expectedPref:  200,
+			expectedFound: true,
+		},
+		{
+			name: "Negative preference",
+			path: &BGPPath{
+				BGPPathA:    &BGPPathA{},
+				Communities: &types.Communities{65000<<16 + 50},
+			},
+			expectedPref:  -50,
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		pref, found := test.path.PreferenceFromCommunities(table)
+		assert.Equal(t, test.expectedPref, pref, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+	}
+}
+
+func TestSelectCommunityPreference(t *testing.T) {
+	opts := BGPSelectOptions{
+		CommunityPreference: map[uint32]int{
+			65000<<16 + 100: 100,
+			65000<<16 + 200: 200,
+		},
+	}
+
+	newPath := func(localPref uint32, coms ...uint32) *BGPPath {
+		c := types.Communities(coms)
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: localPref,
+				Source:    bnet.IPv4(0).Ptr(),
+				NextHop:   bnet.IPv4(0).Ptr(),
+			},
+			Communities: &c,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		p        *BGPPath
+		q        *BGPPath
+		expected int8
+	}{
+		{
+			name:     "Higher preference wins over higher local pref",
+			p:        newPath(100, 65000<<16+200),
+			q:        newPath(200, 65000<<16+100),
+			expected: 1,
+		},
+		{
+			name:     "Path with preference wins over path without",
+			p:        newPath(100),
+			q:        newPath(100, 65000<<16+100),
+			expected: -1,
+		},
+		{
+			name:     "Tie falls through to local pref",
+			p:        newPath(200, 65000<<16+100),
+			q:        newPath(100, 65000<<16+100, 65000<<16+1),
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.p.SelectWithOptions(test.q, opts), test.name)
+	}
+}
+
+func TestEffectiveNextHop(t *testing.T) {
+	resolver := func(addr bnet.IP) (*bnet.IP, bool) {
+		if addr == bnet.IPv4FromOctets(10, 0, 0, 1) {
+			return bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), true
+		}
+
+		return nil, false
+	}
+
+	tests := []struct {
+		name       string
+		path       *BGPPath
+		expected   *bnet.IP
+		expectedOK bool
+	}{
+		{
+			name: "One level of recursion",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+			},
+			expected:   bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(),
+			expectedOK: true,
+		},
+		{
+			name: "Next hop not resolvable",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				},
+			},
+		},
+		{
+			name: "No next hop",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		nh, ok := test.path.EffectiveNextHop(resolver)
+		assert.Equal(t, test.expectedOK, ok, test.name)
+		assert.Equal(t, test.expected, nh, test.name)
+	}
+}
+
+func TestToProtoInto(t *testing.T) {
+	tests := []struct {
+		name string
+		path *BGPPath
+		dst  *api.BGPPath
+	}{
+		{
+			name: "Empty destination",
+			path: &BGPPath{
+				PathIdentifier: 100,
+				BGPPathA: &BGPPathA{
+					NextHop:       bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					Source:        bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+					LocalPref:     1000,
+					MED:           20,
+					Origin:        1,
+					EBGP:          true,
+					BGPIdentifier: 123,
+					OriginatorID:  8888,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 201701},
+					},
+				},
+				Communities: &types.Communities{100, 200, 300},
+				LargeCommunities: &types.LargeCommunities{
+					{
+						GlobalAdministrator: 222,
+						DataPart1:           500,
+						DataPart2:           600,
+					},
+				},
+				UnknownAttributes: []types.UnknownPathAttribute{
+					{
+						Optional: true,
+						TypeCode: 233,
+						Value:    []byte{200, 222},
+					},
+				},
+				ClusterList: &types.ClusterList{999, 199},
+			},
+			dst: &api.BGPPath{},
+		},
+		{
+			name: "Recycled destination",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+					Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				},
+				Communities: &types.Communities{100},
+			},
+			dst: &api.BGPPath{
+				PathIdentifier: 1,
+				Med:            1000,
+				Communities:    []uint32{1, 2, 3, 4},
+				ClusterList:    []uint32{1, 2},
+				LargeCommunities: []*api.LargeCommunity{
+					{
+						GlobalAdministrator: 1,
+					},
+				},
+				UnknownAttributes: []*api.UnknownPathAttribute{
+					{
+						TypeCode: 1,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test.path.ToProtoInto(test.dst)
+		assert.Equal(t, test.path.ToProto(), test.dst, test.name)
+	}
+}
+
+func TestToProtoIntoReusesExtendedCommunities(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+		ExtendedCommunities: &[]types.ExtendedCommunity{
+			{
+				Type:    types.ExtendedCommunityTypeTwoOctetAS,
+				SubType: types.ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+		},
+	}
+
+	buf := make([]*api.ExtendedCommunity, 2, 4)
+	dst := &api.BGPPath{
+		ExtendedCommunities: buf,
+	}
+
+	p.ToProtoInto(dst)
+	assert.Equal(t, p.ToProto(), dst)
+	assert.Equal(t, 4, cap(dst.ExtendedCommunities))
+	assert.True(t, &buf[0] == &dst.ExtendedCommunities[0])
+}
+
+func TestToProtoClusterList(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+		ASPath:      &types.ASPath{},
+		ClusterList: &types.ClusterList{300, 100, 200},
+	}
+
+	pb := p.ToProto()
+	assert.Equal(t, []uint32{300, 100, 200}, pb.ClusterList)
+	assert.Equal(t, p.ClusterList, BGPPathFromProtoBGPPath(pb, false).ClusterList)
+
+	p.ClusterList = nil
+	assert.Nil(t, p.ToProto().ClusterList)
+
+	// A recycled destination must not keep a stale cluster list
+	p.ToProtoInto(pb)
+	assert.Nil(t, pb.ClusterList)
+}
+
+func TestToProtoIntoNil(t *testing.T) {
+	var p *BGPPath
+	dst := &api.BGPPath{
+		PathIdentifier: 1,
+		Communities:    []uint32{1, 2},
+	}
+
+	p.ToProtoInto(dst)
+	assert.True(t, proto.Equal(&api.BGPPath{}, dst))
+}
+
+func BenchmarkToProtoInto(b *testing.B) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+		Communities: &types.Communities{100, 200, 300},
+	}
+
+	dst := &api.BGPPath{}
+	for i := 0; i < b.N; i++ {
+		p.ToProtoInto(dst)
+	}
+}
+
+func TestNextHopReachable(t *testing.T) {
+	resolver := func(addr bnet.IP) bool {
+		return addr == bnet.IPv4FromOctets(10, 0, 0, 1)
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name: "Reachable",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Unreachable",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "No next hop",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.NextHopReachable(resolver), test.name)
+	}
+}
+
+func TestUsable(t *testing.T) {
+	opts := BGPSelectOptions{
+		NextHopResolver: func(addr bnet.IP) bool {
+			return addr == bnet.IPv4FromOctets(10, 0, 0, 1)
+		},
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name: "Reachable",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+				AFI: afiIPv4,
+			},
+			expected: true,
+		},
+		{
+			name: "Unreachable",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				},
+				AFI: afiIPv4,
+			},
+			expected: false,
+		},
+		{
+			name: "Next hop family mismatch",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{
+					NextHop: bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+				},
+				AFI: afiIPv4,
+			},
+			expected: false,
+		},
+		{
+			name: "No next hop",
+			path: &BGPPath{
+				BGPPathA: &BGPPathA{},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.Usable(opts), test.name)
+	}
+}
+
+func TestSelectNextHopReachable(t *testing.T) {
+	opts := BGPSelectOptions{
+		NextHopResolver: func(addr bnet.IP) bool {
+			return addr == bnet.IPv4FromOctets(10, 0, 0, 1)
+		},
+	}
+
+	reachable := &BGPPath{
+		BGPPathA: &BGPPathA{
+			LocalPref: 100,
+			Source:    bnet.IPv4(0).Ptr(),
+			NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+	}
+
+	unreachable := &BGPPath{
+		BGPPathA: &BGPPathA{
+			LocalPref: 200,
+			Source:    bnet.IPv4(0).Ptr(),
+			NextHop:   bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+	}
+
+	assert.Equal(t, int8(1), reachable.SelectWithOptions(unreachable, opts))
+	assert.Equal(t, int8(-1), unreachable.SelectWithOptions(reachable, opts))
+	assert.Equal(t, int8(-1), reachable.Select(unreachable))
+}
+
+func TestASPathAbbreviated(t *testing.T) {
+	longPath := make([]uint32, 50)
+	for i := range longPath {
+		longPath[i] = uint32(i + 1)
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		head     int
+		tail     int
+		expected string
+	}{
+		{
+			name: "50 ASNs, head 2, tail 2",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: longPath,
+					},
+				},
+			},
+			head:     2,
+			tail:     2,
+			expected: "1 2 … 49 50",
+		},
+		{
+			name: "Short path not abbreviated",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{1, 2, 3},
+					},
+				},
+			},
+			head:     2,
+			tail:     2,
+			expected: "1 2 3",
+		},
+		{
+			name: "Head only",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{1, 2, 3},
+					},
+				},
+			},
+			head:     1,
+			expected: "1 …",
+		},
+		{
+			name:     "No AS path",
+			path:     &BGPPath{},
+			head:     2,
+			tail:     2,
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.ASPathAbbreviated(test.head, test.tail), test.name)
+	}
+}
+
+func TestHasGracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name: "No communities",
+			path: &BGPPath{},
+		},
+		{
+			name: "Other communities",
+			path: &BGPPath{
+				Communities: &types.Communities{65000<<16 + 1, types.WellKnownCommunityNoExport},
+			},
+		},
+		{
+			name: "GRACEFUL_SHUTDOWN present",
+			path: &BGPPath{
+				Communities: &types.Communities{65000<<16 + 1, 65535 << 16},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.HasGracefulShutdown(), test.name)
+	}
+}
+
+func TestHasBlackholeCommunity(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name: "No communities",
+			path: &BGPPath{},
+		},
+		{
+			name: "Other communities",
+			path: &BGPPath{
+				Communities: &types.Communities{65000<<16 + 666, 65535<<16 + 667},
+			},
+		},
+		{
+			name: "BLACKHOLE present",
+			path: &BGPPath{
+				Communities: &types.Communities{65000<<16 + 1, 65535<<16 + 666},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.HasBlackholeCommunity(), test.name)
+	}
+}
+
+func TestLengthExtendedLength(t *testing.T) {
+	coms := make(types.Communities, 65)
+	clusterList := make(types.ClusterList, 64)
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4(0).Ptr(),
+			Source:  bnet.IPv4(0).Ptr(),
+		},
+		ASPath:      &types.ASPath{},
+		Communities: &coms,
+		ClusterList: &clusterList,
+	}
+
+	sizes := p.AttributeSizes()
+	assert.Equal(t, uint16(4+65*4), sizes[AttributeSizeCommunities])
+	assert.Equal(t, uint16(4+64*4), sizes[AttributeSizeClusterList])
+	assert.Equal(t, uint16(32+3+264+260), p.Length())
+}
+
+func TestNextHopSize(t *testing.T) {
+	newPath := func(afi uint16, safi uint8, nextHop bnet.IP, linkLocal *bnet.IP) *BGPPath {
+		return &BGPPath{
+			AFI:  afi,
+			SAFI: safi,
+			BGPPathA: &BGPPathA{
+				NextHop:          nextHop.Ptr(),
+				NextHopLinkLocal: linkLocal,
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected uint16
+	}{
+		{
+			name:     "IPv4 unicast",
+			path:     newPath(afiIPv4, safiUnicast, bnet.IPv4FromOctets(10, 0, 0, 1), nil),
+			expected: 7,
+		},
+		{
+			name:     "Unknown family, IPv4 next hop",
+			path:     newPath(0, 0, bnet.IPv4FromOctets(10, 0, 0, 1), nil),
+			expected: 7,
+		},
+		{
+			name:     "Unknown family, IPv6 next hop",
+			path:     newPath(0, 0, bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1), nil),
+			expected: 24,
+		},
+		{
+			name:     "IPv6 unicast, IPv4 next hop mapped to IPv6",
+			path:     newPath(afiIPv6, safiUnicast, bnet.IPv4FromOctets(10, 0, 0, 1), nil),
+			expected: 24,
+		},
+		{
+			name:     "IPv6 unicast with link local next hop",
+			path:     newPath(afiIPv6, safiUnicast, bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1), bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr()),
+			expected: 40,
+		},
+		{
+			name:     "IPv4 multicast",
+			path:     newPath(afiIPv4, 2, bnet.IPv4FromOctets(10, 0, 0, 1), nil),
+			expected: 12,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.path.AttributeSizes()[AttributeSizeNextHop])
+			assert.Equal(t, 25+3+test.expected, test.path.Length())
+		})
+	}
+}
+
+func TestAttributeSizes(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:         bnet.IPv4(0).Ptr(),
+			Source:          bnet.IPv4(0).Ptr(),
+			OriginatorID:    23,
+			AtomicAggregate: true,
+			AIGP:            100,
+			AIGPPresent:     true,
+		},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{15169, 199714},
+			},
+		},
+		Communities: &types.Communities{100, 200},
+		LargeCommunities: &types.LargeCommunities{
+			{
+				GlobalAdministrator: 1,
+				DataPart1:           2,
+				DataPart2:           3,
+			},
+		},
+		ExtendedCommunities: &[]types.ExtendedCommunity{
+			{
+				Type:    types.ExtendedCommunityTypeTwoOctetAS,
+				SubType: types.ExtendedCommunitySubTypeRouteTarget,
+			},
+		},
+		ClusterList: &types.ClusterList{10, 20, 30},
+		UnknownAttributes: []types.UnknownPathAttribute{
+			{
+				Optional:   true,
+				Transitive: true,
+				TypeCode:   233,
+				Value:      []byte{1, 2, 3},
+			},
+		},
+		PMSITunnel: &types.PMSITunnel{
+			TunnelType:       types.PMSITunnelTypeIngressReplication,
+			TunnelIdentifier: []byte{10, 0, 0, 1},
+		},
+		BGPLSAttribute: &types.BGPLSAttribute{
+			Value: []byte{0x04, 0x47, 0, 0},
+		},
+	}
+
+	expected := map[string]uint16{
+		AttributeSizeBase:                25,
+		AttributeSizeNextHop:             7,
+		AttributeSizeASPath:              13,
+		AttributeSizeCommunities:         11,
+		AttributeSizeLargeCommunities:    15,
+		AttributeSizeExtendedCommunities: 11,
+		AttributeSizeClusterList:         15,
+		AttributeSizeOriginatorID:        4,
+		AttributeSizeAtomicAggregate:     3,
+		AttributeSizePMSITunnel:          12,
+		AttributeSizeBGPLS:               7,
+		AttributeSizeAIGP:                14,
+		AttributeSizeUnknown:             6,
+	}
+
+	sizes := p.AttributeSizes()
+	assert.Equal(t, expected, sizes)
+
+	sum := uint16(0)
+	for _, s := range sizes {
+		sum += s
+	}
+
+	assert.Equal(t, p.Length(), sum)
+}
+
+func TestBGPLSAttributeProtoRoundTrip(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4(0).Ptr(),
+			Source:  bnet.IPv4(0).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+		BGPLSAttribute: &types.BGPLSAttribute{
+			Value: []byte{0x04, 0x02, 0, 4, 'r', 't', 'r', '1'},
+		},
+	}
+
+	res := BGPPathFromProtoBGPPath(p.ToProto(), false)
+	assert.Equal(t, p.BGPLSAttribute, res.BGPLSAttribute)
+	assert.Equal(t, p.ComputeHash(), res.ComputeHash())
+
+	p.BGPLSAttribute = nil
+	res = BGPPathFromProtoBGPPath(p.ToProto(), false)
+	assert.Nil(t, res.BGPLSAttribute)
+}
+
+func randomBGPPath(r *rand.Rand) *BGPPath {
+	asns := make([]uint32, 1+r.Intn(3))
+	for i := range asns {
+		asns[i] = uint32(r.Intn(3))
+	}
+
+	nextHop := bnet.IPv4(uint32(r.Intn(3)))
+	if r.Intn(4) == 0 {
+		nextHop = bnet.IPv6(0x20010db8<<32, uint64(r.Intn(3)))
+	}
+
+	return &BGPPath{
+		AFI: uint16(r.Intn(3)),
+		BGPPathA: &BGPPathA{
+			LocalPref:     uint32(100 + 50*r.Intn(3)),
+			MED:           uint32(r.Intn(3)),
+			Origin:        uint8(r.Intn(3)),
+			EBGP:          r.Intn(2) == 1,
+			BGPIdentifier: uint32(r.Intn(3)),
+			OriginatorID:  uint32(r.Intn(2)),
+			Source:        bnet.IPv4(uint32(r.Intn(3))).Ptr(),
+			NextHop:       nextHop.Ptr(),
+		},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: asns,
+			},
+		},
+		ASPathLen: uint16(len(asns)),
+	}
+}
+
+func TestSelectFastPathEquivalence(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		p := randomBGPPath(r)
+		q := randomBGPPath(r)
+
+		res := p.Select(q)
+		if !assert.Equal(t, p.SelectWithOptions(q, BGPSelectOptions{}), res, "Select and SelectWithOptions differ for %s and %s", p, q) {
+			return
+		}
+
+		if !assert.Equal(t, -res, q.Select(p), "Select is not antisymmetric for %s and %s", p, q) {
+			return
+		}
+	}
+}
+
+func BenchmarkSelect(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	paths := make([]*BGPPath, 1024)
+	for i := range paths {
+		paths[i] = randomBGPPath(r)
+	}
+
+	b.Run("Select", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			paths[i%len(paths)].Select(paths[(i+1)%len(paths)])
+		}
+	})
+
+	b.Run("SelectWithOptions", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			paths[i%len(paths)].SelectWithOptions(paths[(i+1)%len(paths)], BGPSelectOptions{})
+		}
+	})
+}
+
+func TestSelectCompareOriginatorID(t *testing.T) {
+	// p has no ORIGINATOR_ID but a higher BGP identifier than q's ORIGINATOR_ID
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			BGPIdentifier: 100,
+			Source:        bnet.IPv4(0).Ptr(),
+			NextHop:       bnet.IPv4(0).Ptr(),
+		},
+	}
+
+	q := &BGPPath{
+		BGPPathA: &BGPPathA{
+			BGPIdentifier: 1,
+			OriginatorID:  50,
+			Source:        bnet.IPv4(0).Ptr(),
+			NextHop:       bnet.IPv4(0).Ptr(),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     BGPSelectOptions
+		expected int8
+	}{
+		{
+			name:     "Zero ORIGINATOR_ID falls back to BGP identifier",
+			expected: 1,
+		},
+		{
+			name: "Zero ORIGINATOR_ID compared as is",
+			opts: BGPSelectOptions{
+				CompareOriginatorID: true,
+			},
+			expected: -1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, p.SelectWithOptions(q, test.opts), test.name)
+		assert.Equal(t, -test.expected, q.SelectWithOptions(p, test.opts), test.name)
+	}
+}
+
+func TestOriginAS(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       *BGPPath
+		expected   uint32
+		expectedOK bool
+	}{
+		{
+			name: "No AS path",
+			path: &BGPPath{},
+		},
+		{
+			name: "Sequence",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 201701},
+					},
+				},
+			},
+			expected:   201701,
+			expectedOK: true,
+		},
+		{
+			name: "Trailing AS set",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320},
+					},
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{100, 200},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		asn, ok := test.path.OriginAS()
+		assert.Equal(t, test.expected, asn, test.name)
+		assert.Equal(t, test.expectedOK, ok, test.name)
+	}
+}
+
+func TestPenultimateAS(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       *BGPPath
+		expected   uint32
+		expectedOK bool
+	}{
+		{
+			name: "No AS path",
+			path: &BGPPath{},
+		},
+		{
+			name: "Single ASN",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{201701},
+					},
+				},
+			},
+		},
+		{
+			name: "Sequence",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 6939, 201701},
+					},
+				},
+			},
+			expected:   6939,
+			expectedOK: true,
+		},
+		{
+			name: "Origin prepended across segments",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 6939},
+					},
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{201701, 201701},
+					},
+				},
+			},
+			expected:   6939,
+			expectedOK: true,
+		},
+		{
+			name: "AS set before origin",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{100, 200},
+					},
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{201701},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		asn, ok := test.path.PenultimateAS()
+		assert.Equal(t, test.expected, asn, test.name)
+		assert.Equal(t, test.expectedOK, ok, test.name)
+	}
+}
+
+func TestReplaceASN(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		from     uint32
+		to       uint32
+		expected *BGPPath
+	}{
+		{
+			name: "Replace in sequence and set",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 65001, 65001, 201701},
+					},
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{65001, 65002},
+					},
+				},
+				ASPathLen: 5,
+			},
+			from: 65001,
+			to:   201701,
+			expected: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 201701, 201701, 201701},
+					},
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{201701, 65002},
+					},
+				},
+				ASPathLen: 5,
+			},
+		},
+		{
+			name: "ASN not present",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320},
+					},
+				},
+				ASPathLen: 1,
+			},
+			from: 65001,
+			to:   201701,
+			expected: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320},
+					},
+				},
+				ASPathLen: 1,
+			},
+		},
+		{
+			name:     "No AS path",
+			path:     &BGPPath{},
+			from:     65001,
+			to:       201701,
+			expected: &BGPPath{},
+		},
+	}
+
+	for _, test := range tests {
+		test.path.ReplaceASN(test.from, test.to)
+		assert.Equal(t, test.expected, test.path, test.name)
+	}
+}
+
+func TestReplaceASNDoesNotModifyCopies(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{65001, 3320},
+			},
+		},
+	}
+
+	cp := p.Copy()
+	cp.ReplaceASN(65001, 201701)
+
+	assert.Equal(t, []uint32{65001, 3320}, (*p.ASPath)[0].ASNs)
+	assert.Equal(t, []uint32{201701, 3320}, (*cp.ASPath)[0].ASNs)
+}
+
+func TestToProtoStableOrder(t *testing.T) {
+	newPath := func(coms types.Communities, lcoms types.LargeCommunities) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+			},
+			Communities:      &coms,
+			LargeCommunities: &lcoms,
+			ClusterList:      &types.ClusterList{30, 10},
+		}
+	}
+
+	a := newPath(types.Communities{300, 100, 200}, types.LargeCommunities{
+		{GlobalAdministrator: 2, DataPart1: 1, DataPart2: 1},
+		{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 1},
+		{GlobalAdministrator: 1, DataPart1: 1, DataPart2: 2},
+	})
+
+	b := newPath(types.Communities{200, 300, 100}, types.LargeCommunities{
+		{GlobalAdministrator: 1, DataPart1: 1, DataPart2: 2},
+		{GlobalAdministrator: 2, DataPart1: 1, DataPart2: 1},
+		{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 1},
+	})
+
+	pa := a.ToProto()
+	assert.Equal(t, pa, b.ToProto())
+	assert.Equal(t, []uint32{100, 200, 300}, pa.Communities)
+	assert.Equal(t, []uint32{30, 10}, pa.ClusterList)
+	assert.Equal(t, types.Communities{300, 100, 200}, *a.Communities)
+}
+
+func TestAddressScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		nextHop  *bnet.IP
+		expected bnet.Scope
+	}{
+		{
+			name:     "Global",
+			nextHop:  bnet.IPv4FromOctets(185, 65, 240, 1).Ptr(),
+			expected: bnet.ScopeGlobal,
+		},
+		{
+			name:     "Loopback",
+			nextHop:  bnet.IPv4FromOctets(127, 0, 0, 1).Ptr(),
+			expected: bnet.ScopeLoopback,
+		},
+		{
+			name:     "Link local",
+			nextHop:  bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: bnet.ScopeLinkLocal,
+		},
+		{
+			name:     "ULA",
+			nextHop:  bnet.IPv6FromBlocks(0xfd00, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: bnet.ScopeUniqueLocal,
+		},
+		{
+			name:     "Documentation",
+			nextHop:  bnet.IPv4FromOctets(198, 51, 100, 1).Ptr(),
+			expected: bnet.ScopeDocumentation,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: test.nextHop,
+			},
+		}
+
+		assert.Equal(t, test.expected, p.AddressScope(), test.name)
+	}
+}
+
+func TestContainsSequence(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{1, 2},
+			},
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3, 4},
+			},
+			{
+				Type: types.ASSet,
+				ASNs: []uint32{5, 6},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		asns     []uint32
+		expected bool
+	}{
+		{
+			name:     "Match",
+			asns:     []uint32{2, 3},
+			expected: true,
+		},
+		{
+			name:     "Wrong order",
+			asns:     []uint32{3, 2},
+			expected: false,
+		},
+		{
+			name:     "Not consecutive",
+			asns:     []uint32{1, 3},
+			expected: false,
+		},
+		{
+			name:     "AS set not considered",
+			asns:     []uint32{4, 5},
+			expected: false,
+		},
+		{
+			name:     "Empty",
+			asns:     []uint32{},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, p.ContainsSequence(test.asns), test.name)
+	}
+}
+
+func TestBGPPathASSetRendering(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:  bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320, 201701},
+			},
+			{
+				Type: types.ASSet,
+				ASNs: []uint32{65001, 65002},
+			},
+		},
+		ASPathLen:        3,
+		Communities:      &types.Communities{},
+		LargeCommunities: &types.LargeCommunities{},
+	}
+
+	assert.Equal(t, "Local Pref: 0, Origin: IGP, AS Path: 3320 201701 {65001,65002}, BGP type: internal, NEXT HOP: 10.0.0.1, MED: 0, Path ID: 0, Source: 10.0.0.2, Communities: [], LargeCommunities: [], Source Protocol: BGP", p.String())
+	assert.Contains(t, p.Print(), "\t\tAS Path: 3320 201701 {65001,65002}\n")
+	assert.Equal(t, "*> 10.0.0.1                 0      0      0 3320 201701 {65001,65002} i", p.ShowFormat(true))
+}
+
+func TestSuggestLargeEquivalent(t *testing.T) {
+	p := &BGPPath{
+		Communities: &types.Communities{65001<<16 + 100, 65535<<16 + 666},
+	}
+
+	assert.Equal(t, []types.LargeCommunity{
+		{
+			GlobalAdministrator: 201701,
+			DataPart1:           65001,
+			DataPart2:           100,
+		},
+		{
+			GlobalAdministrator: 201701,
+			DataPart1:           65535,
+			DataPart2:           666,
+		},
+	}, p.SuggestLargeEquivalent(201701))
+
+	assert.Equal(t, []types.LargeCommunity{}, (&BGPPath{}).SuggestLargeEquivalent(201701))
+}
+
+func TestRequiresFourOctetASN(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     *BGPPath
+		expected bool
+	}{
+		{
+			name: "No AS path",
+			path: &BGPPath{},
+		},
+		{
+			name: "Two octet ASNs only",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320, 65535},
+					},
+				},
+			},
+		},
+		{
+			name: "Four octet ASN in AS set",
+			path: &BGPPath{
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{3320},
+					},
+					{
+						Type: types.ASSet,
+						ASNs: []uint32{100, 201701},
+					},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.path.RequiresFourOctetASN(), test.name)
+	}
+}
+
+func TestBGPPathsFromProto(t *testing.T) {
+	valid := func(pathID uint32) *api.BGPPath {
+		return &api.BGPPath{
+			PathIdentifier: pathID,
+			NextHop:        bnet.IPv4FromOctets(10, 0, 0, 1).ToProto(),
+			Source:         bnet.IPv4FromOctets(10, 0, 0, 2).ToProto(),
+		}
+	}
+
+	noNextHop := valid(3)
+	noNextHop.NextHop = nil
+
+	nilLargeCommunity := valid(4)
+	nilLargeCommunity.LargeCommunities = []*api.LargeCommunity{nil}
+
+	nilSegment := valid(5)
+	nilSegment.AsPath = []*api.ASPathSegment{nil}
+
+	paths, errs := BGPPathsFromProto([]*api.BGPPath{
+		valid(1),
+		nil,
+		noNextHop,
+		valid(2),
+		nilLargeCommunity,
+		nilSegment,
+	})
+
+	assert.Len(t, paths, 2)
+	assert.Equal(t, uint32(1), paths[0].PathIdentifier)
+	assert.Equal(t, uint32(2), paths[1].PathIdentifier)
+	assert.Equal(t, bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(), paths[0].BGPPathA.NextHop)
+
+	assert.Len(t, errs, 4)
+	assert.EqualError(t, errs[0], "path 1: path is nil")
+	assert.EqualError(t, errs[1], "path 2: next hop is nil")
+	assert.EqualError(t, errs[2], "path 4: large community 0 is nil")
+	assert.EqualError(t, errs[3], "path 5: AS path segment 0 is nil")
+}
+
+func TestECMPWithOptions(t *testing.T) {
+	newPath := func(asns ...uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+			ASPathLen: uint16(len(asns)),
+		}
+	}
+
+	best := newPath(3320, 201701)
+	candidates := []*BGPPath{
+		newPath(3320, 201701),
+		newPath(6939, 201701),
+		newPath(174, 201701),
+		newPath(174, 3320, 201701),
+	}
+
+	count := func(opts BGPSelectOptions) int {
+		n := 0
+		for _, c := range candidates {
+			if best.ECMPWithOptions(c, opts) {
+				n++
+			}
+		}
+
+		return n
+	}
+
+	assert.Equal(t, 1, count(BGPSelectOptions{}))
+	assert.Equal(t, 3, count(BGPSelectOptions{ASPathMultipathRelax: true}))
+	assert.True(t, best.ECMP(candidates[1]))
+}
+
+func TestECMPWith(t *testing.T) {
+	newPath := func(med uint32, asns ...uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				MED:       med,
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+			ASPathLen: uint16(len(asns)),
+		}
+	}
+
+	strict := ECMPCriteria{
+		LocalPref:  true,
+		ASPathLen:  true,
+		Origin:     true,
+		MED:        true,
+		AIGP:       true,
+		NeighborAS: true,
+	}
+
+	relaxed := ECMPCriteria{
+		LocalPref: true,
+		ASPathLen: true,
+		Origin:    true,
+	}
+
+	tests := []struct {
+		name            string
+		other           *BGPPath
+		expectedStrict  bool
+		expectedRelaxed bool
+	}{
+		{
+			name:            "Identical",
+			other:           newPath(0, 3320, 201701),
+			expectedStrict:  true,
+			expectedRelaxed: true,
+		},
+		{
+			name:            "Different neighbor AS",
+			other:           newPath(0, 6939, 201701),
+			expectedStrict:  false,
+			expectedRelaxed: true,
+		},
+		{
+			name:            "Different MED",
+			other:           newPath(10, 3320, 201701),
+			expectedStrict:  false,
+			expectedRelaxed: true,
+		},
+		{
+			name: "AIGP on one path only",
+			other: func() *BGPPath {
+				p := newPath(0, 3320, 201701)
+				p.BGPPathA.AIGP = 10
+				p.BGPPathA.AIGPPresent = true
+				return p
+			}(),
+			expectedStrict:  true,
+			expectedRelaxed: true,
+		},
+		{
+			name:            "Different AS path length",
+			other:           newPath(0, 3320, 3320, 201701),
+			expectedStrict:  false,
+			expectedRelaxed: false,
+		},
+	}
+
+	best := newPath(0, 3320, 201701)
+	for _, test := range tests {
+		assert.Equal(t, test.expectedStrict, best.ECMPWith(test.other, strict), test.name)
+		assert.Equal(t, test.expectedRelaxed, best.ECMPWith(test.other, relaxed), test.name)
+	}
+
+	assert.True(t, best.ECMPWith(newPath(5, 1, 2, 3, 4), ECMPCriteria{}))
+
+	withAIGP := func(aigp uint64) *BGPPath {
+		p := newPath(0, 3320, 201701)
+		p.BGPPathA.AIGP = aigp
+		p.BGPPathA.AIGPPresent = true
+		return p
+	}
+	assert.False(t, withAIGP(10).ECMPWith(withAIGP(20), strict))
+	assert.True(t, withAIGP(10).ECMPWith(withAIGP(20), relaxed))
+}
+
+func TestPathIdentifierBytes(t *testing.T) {
+	p := &BGPPath{
+		PathIdentifier: 0x01020304,
+	}
+
+	b := p.PathIdentifierBytes()
+	assert.Equal(t, [4]byte{1, 2, 3, 4}, b)
+
+	id, err := PathIdentifierFromBytes(b[:])
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x01020304), id)
+
+	_, err = PathIdentifierFromBytes([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestSelectIGPMetric(t *testing.T) {
+	newPath := func(igpMetric uint32, routerID uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref:     100,
+				BGPIdentifier: routerID,
+				IGPMetric:     igpMetric,
+				Source:        bnet.IPv4(0).Ptr(),
+				NextHop:       bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		b                *BGPPath
+		c                *BGPPath
+		expected         int8
+		expectBetterECMP bool
+	}{
+		{
+			name:             "Lower IGP metric wins over higher router ID",
+			b:                newPath(10, 1),
+			c:                newPath(20, 2),
+			expected:         1,
+			expectBetterECMP: true,
+		},
+		{
+			name:     "Higher IGP metric loses",
+			b:        newPath(20, 2),
+			c:        newPath(10, 1),
+			expected: -1,
+		},
+		{
+			name:     "Equal IGP metric falls through to router ID",
+			b:        newPath(10, 1),
+			c:        newPath(10, 2),
+			expected: -1,
+		},
+		{
+			name:     "Zero IGP metric on both sides falls through to router ID",
+			b:        newPath(0, 1),
+			c:        newPath(0, 2),
+			expected: -1,
+		},
+		{
+			name: "EBGP is preferred before IGP metric",
+			b: &BGPPath{
+				BGPPathA: &BGPPathA{
+					LocalPref: 100,
+					EBGP:      true,
+					IGPMetric: 100,
+					Source:    bnet.IPv4(0).Ptr(),
+					NextHop:   bnet.IPv4(0).Ptr(),
+				},
+				ASPath: &types.ASPath{},
+			},
+			c:        newPath(10, 1),
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.b.Select(test.c), test.name)
+		assert.Equal(t, test.expectBetterECMP, test.c.betterECMP(test.b), test.name)
+	}
+
+	p := newPath(10, 1)
+	q := newPath(20, 1)
+	assert.Equal(t, p.ComputeHash(), q.ComputeHash())
+}
+
+func TestComputeHashCopyStable(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop:      bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				Source:       bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				LocalPref:    100,
+				MED:          10,
+				OriginatorID: 23,
+			},
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{3320, 201701},
+				},
+			},
+			Communities: &types.Communities{65000<<16 | 100},
+			LargeCommunities: &types.LargeCommunities{
+				{GlobalAdministrator: 65000, DataPart1: 1, DataPart2: 2},
+			},
+			ClusterList: &types.ClusterList{1, 2},
+		}
+	}
+
+	p := newPath()
+	assert.Equal(t, p.ComputeHash(), p.Copy().ComputeHash())
+	assert.Equal(t, p.ComputeHashWithPathID(), p.Copy().ComputeHashWithPathID())
+
+	q := newPath()
+	assert.Equal(t, p.ComputeHash(), q.ComputeHash())
+
+	// The hash must reflect the contents of the attributes
+	(*q.Communities)[0] = 65000<<16 | 200
+	assert.NotEqual(t, p.ComputeHash(), q.ComputeHash())
+}
+
+func TestComputeHashConfedSegments(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320},
+			},
+		},
+	}
+
+	q := p.Copy()
+	q.ASPath = &types.ASPath{
+		{
+			Type: types.ASConfedSequence,
+			ASNs: []uint32{65000},
+		},
+		{
+			Type: types.ASSequence,
+			ASNs: []uint32{3320},
+		},
+	}
+
+	assert.NotEqual(t, p.ComputeHash(), q.ComputeHash())
+	assert.NotEqual(t, p.ComputeHashWithPathID(), q.ComputeHashWithPathID())
+}
+
+func TestComputeHashCoversAttributesEqual(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+				Source:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *BGPPath)
+	}{
+		{
+			name: "MED presence",
+			modify: func(p *BGPPath) {
+				p.BGPPathA.HasMED = true
+			},
+		},
+		{
+			name: "Link local next hop",
+			modify: func(p *BGPPath) {
+				p.BGPPathA.NextHopLinkLocal = bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr()
+			},
+		},
+		{
+			name: "Atomic aggregate",
+			modify: func(p *BGPPath) {
+				p.BGPPathA.AtomicAggregate = true
+			},
+		},
+		{
+			name: "Aggregator",
+			modify: func(p *BGPPath) {
+				p.BGPPathA.Aggregator = &types.Aggregator{
+					ASN:     65000,
+					Address: 1,
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p := newPath()
+		q := newPath()
+		test.modify(q)
+
+		assert.False(t, p.AttributesEqual(q), test.name)
+		assert.NotEqual(t, p.ComputeHash(), q.ComputeHash(), test.name)
+		assert.NotEqual(t, p.ComputeHashWithPathID(), q.ComputeHashWithPathID(), test.name)
+	}
+}
+
+func TestComputeHashNilAttributes(t *testing.T) {
+	newPath := func() *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+			},
+		}
+	}
+
+	p := newPath()
+	q := newPath()
+	assert.Equal(t, p.ComputeHash(), q.ComputeHash())
+	assert.Equal(t, p.ComputeHashWithPathID(), q.ComputeHashWithPathID())
+
+	q.BGPPathA.NextHop = bnet.IPv4FromOctets(10, 0, 0, 1).Ptr()
+	assert.NotEqual(t, p.ComputeHash(), q.ComputeHash())
+}
+
+func TestNextHopFamilyMatchesNLRI(t *testing.T) {
+	tests := []struct {
+		name     string
+		afi      uint16
+		nextHop  *bnet.IP
+		expected bool
+	}{
+		{
+			name:     "IPv4 next hop for IPv4 NLRI",
+			afi:      afiIPv4,
+			nextHop:  bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			expected: true,
+		},
+		{
+			name:     "IPv6 next hop for IPv4 NLRI",
+			afi:      afiIPv4,
+			nextHop:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: false,
+		},
+		{
+			name:     "IPv4-mapped IPv6 next hop for IPv4 NLRI",
+			afi:      afiIPv4,
+			nextHop:  bnet.IPv6FromBlocks(0, 0, 0, 0, 0, 0xffff, 0x0a00, 0x0001).Ptr(),
+			expected: true,
+		},
+		{
+			name:     "IPv6 next hop for IPv6 NLRI",
+			afi:      afiIPv6,
+			nextHop:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: true,
+		},
+		{
+			name:     "IPv4 next hop for IPv6 NLRI",
+			afi:      afiIPv6,
+			nextHop:  bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			expected: true,
+		},
+		{
+			name:     "No next hop",
+			afi:      afiIPv4,
+			expected: false,
+		},
+		{
+			name:     "Unknown family",
+			nextHop:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: test.nextHop,
+			},
+			AFI: test.afi,
+		}
+
+		assert.Equal(t, test.expected, p.NextHopFamilyMatchesNLRI(), test.name)
+	}
+}
+
+func TestSelectNextHopFamilyMismatch(t *testing.T) {
+	good := &BGPPath{
+		BGPPathA: &BGPPathA{
+			LocalPref: 100,
+			NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			Source:    bnet.IPv4(0).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+		AFI:    afiIPv4,
+	}
+
+	bad := &BGPPath{
+		BGPPathA: &BGPPathA{
+			LocalPref: 200,
+			NextHop:   bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			Source:    bnet.IPv4(0).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+		AFI:    afiIPv4,
+	}
+
+	assert.Equal(t, int8(1), good.SelectWithOptions(bad, BGPSelectOptions{}))
+	assert.Equal(t, int8(-1), bad.SelectWithOptions(good, BGPSelectOptions{}))
+}
+
+func TestContainsReservedASN(t *testing.T) {
+	tests := []struct {
+		name          string
+		asns          []uint32
+		expected      uint32
+		expectedFound bool
+	}{
+		{
+			name: "No reserved ASN",
+			asns: []uint32{3320, 201701, 64512},
+		},
+		{
+			name:          "Documentation ASN",
+			asns:          []uint32{3320, 64496, 201701},
+			expected:      64496,
+			expectedFound: true,
+		},
+		{
+			name:          "32 bit documentation ASN",
+			asns:          []uint32{3320, 65551},
+			expected:      65551,
+			expectedFound: true,
+		},
+		{
+			name:          "AS_TRANS",
+			asns:          []uint32{23456},
+			expected:      23456,
+			expectedFound: true,
+		},
+		{
+			name:          "AS 0",
+			asns:          []uint32{3320, 0},
+			expected:      0,
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: test.asns,
+				},
+			},
+		}
+
+		asn, found := p.ContainsReservedASN()
+		assert.Equal(t, test.expected, asn, test.name)
+		assert.Equal(t, test.expectedFound, found, test.name)
+	}
+}
+
+func TestASPathFingerprint(t *testing.T) {
+	newPath := func(segments ...types.ASPathSegment) *BGPPath {
+		asPath := types.ASPath(segments)
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: uint32(len(segments)),
+			},
+			ASPath: &asPath,
+		}
+	}
+
+	a := newPath(types.ASPathSegment{Type: types.ASSequence, ASNs: []uint32{3320, 201701}})
+	b := newPath(types.ASPathSegment{Type: types.ASSequence, ASNs: []uint32{3320, 201701}})
+	b.BGPPathA.MED = 100
+
+	assert.Equal(t, a.ASPathFingerprint(), b.ASPathFingerprint())
+
+	different := []*BGPPath{
+		newPath(types.ASPathSegment{Type: types.ASSequence, ASNs: []uint32{201701, 3320}}),
+		newPath(types.ASPathSegment{Type: types.ASSet, ASNs: []uint32{3320, 201701}}),
+		newPath(
+			types.ASPathSegment{Type: types.ASSequence, ASNs: []uint32{3320}},
+			types.ASPathSegment{Type: types.ASSequence, ASNs: []uint32{201701}},
+		),
+		{},
+	}
+
+	for _, d := range different {
+		assert.NotEqual(t, a.ASPathFingerprint(), d.ASPathFingerprint())
+	}
+}
+
+func TestSelectPathIdentifierTieBreak(t *testing.T) {
+	newPath := func(pathID uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				Source:    bnet.IPv4(0).Ptr(),
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			},
+			ASPath:         &types.ASPath{},
+			PathIdentifier: pathID,
+		}
+	}
+
+	r := &Route{
+		paths: []*Path{
+			{Type: BGPPathType, BGPPath: newPath(3)},
+			{Type: BGPPathType, BGPPath: newPath(1)},
+			{Type: BGPPathType, BGPPath: newPath(2)},
+		},
+	}
+
+	assert.True(t, r.paths[0].BGPPath.AttributesEqual(r.paths[1].BGPPath))
+	assert.Equal(t, int8(1), newPath(1).Select(newPath(2)))
+	assert.Equal(t, int8(-1), newPath(2).Select(newPath(1)))
+
+	r.PathSelection()
+	for i, expected := range []uint32{1, 2, 3} {
+		assert.Equal(t, expected, r.paths[i].BGPPath.PathIdentifier)
+	}
+}
+
+func TestSelectRouteServer(t *testing.T) {
+	newPath := func(nextHop uint8, med uint32, source uint8) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				MED:       med,
+				EBGP:      true,
+				Source:    bnet.IPv4FromOctets(192, 0, 2, source).Ptr(),
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, nextHop).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		b        *BGPPath
+		c        *BGPPath
+		expected int8
+	}{
+		{
+			name:     "Different next hops are co-best",
+			b:        newPath(1, 0, 1),
+			c:        newPath(2, 0, 1),
+			expected: 0,
+		},
+		{
+			name:     "Path IDs are not compared",
+			b:        &BGPPath{BGPPathA: newPath(1, 0, 1).BGPPathA, ASPath: &types.ASPath{}, PathIdentifier: 1},
+			c:        &BGPPath{BGPPathA: newPath(2, 0, 1).BGPPathA, ASPath: &types.ASPath{}, PathIdentifier: 2},
+			expected: 0,
+		},
+		{
+			name:     "MED is still compared",
+			b:        newPath(1, 10, 1),
+			c:        newPath(2, 0, 1),
+			expected: -1,
+		},
+		{
+			name:     "Source is still compared",
+			b:        newPath(1, 0, 1),
+			c:        newPath(1, 0, 2),
+			expected: -1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.b.SelectWithOptions(test.c, RouteServerSelectOptions()), test.name)
+	}
+
+	assert.NotEqual(t, int8(0), newPath(1, 0, 1).Select(newPath(2, 0, 1)))
+}
+
+func TestNextHopInPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		nextHop  *bnet.IP
+		pfx      bnet.Prefix
+		expected bool
+	}{
+		{
+			name:     "Next hop inside NLRI",
+			nextHop:  bnet.IPv4FromOctets(192, 0, 2, 1).Ptr(),
+			pfx:      bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24),
+			expected: true,
+		},
+		{
+			name:    "Next hop outside NLRI",
+			nextHop: bnet.IPv4FromOctets(192, 0, 3, 1).Ptr(),
+			pfx:     bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24),
+		},
+		{
+			name:     "IPv6 next hop inside NLRI",
+			nextHop:  bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			pfx:      bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32),
+			expected: true,
+		},
+		{
+			name:    "Address family mismatch",
+			nextHop: bnet.IPv4(0).Ptr(),
+			pfx:     bnet.NewPfx(bnet.IPv6(0, 0), 0),
+		},
+		{
+			name: "No next hop",
+			pfx:  bnet.NewPfx(bnet.IPv4(0), 0),
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			BGPPathA: &BGPPathA{
+				NextHop: test.nextHop,
+			},
+		}
+
+		assert.Equal(t, test.expected, p.NextHopInPrefix(test.pfx), test.name)
+	}
+}
+
+func TestMergeCommunitiesFrom(t *testing.T) {
+	tests := []struct {
+		name                     string
+		b                        *BGPPath
+		other                    *BGPPath
+		includeLarge             bool
+		expectedCommunities      *types.Communities
+		expectedLargeCommunities *types.LargeCommunities
+	}{
+		{
+			name: "Overlapping communities",
+			b: &BGPPath{
+				Communities:      &types.Communities{100, 200, 100},
+				LargeCommunities: &types.LargeCommunities{{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3}},
+			},
+			other: &BGPPath{
+				Communities:      &types.Communities{300, 200},
+				LargeCommunities: &types.LargeCommunities{{GlobalAdministrator: 4, DataPart1: 5, DataPart2: 6}},
+			},
+			expectedCommunities:      &types.Communities{100, 200, 300},
+			expectedLargeCommunities: &types.LargeCommunities{{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3}},
+		},
+		{
+			name: "Including large communities",
+			b: &BGPPath{
+				LargeCommunities: &types.LargeCommunities{{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3}},
+			},
+			other: &BGPPath{
+				Communities: &types.Communities{300},
+				LargeCommunities: &types.LargeCommunities{
+					{GlobalAdministrator: 4, DataPart1: 5, DataPart2: 6},
+					{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3},
+				},
+			},
+			includeLarge:        true,
+			expectedCommunities: &types.Communities{300},
+			expectedLargeCommunities: &types.LargeCommunities{
+				{GlobalAdministrator: 1, DataPart1: 2, DataPart2: 3},
+				{GlobalAdministrator: 4, DataPart1: 5, DataPart2: 6},
+			},
+		},
+		{
+			name:  "Nothing to merge",
+			b:     &BGPPath{},
+			other: &BGPPath{},
+		},
+	}
+
+	for _, test := range tests {
+		var otherComs types.Communities
+		if test.other.Communities != nil {
+			otherComs = append(otherComs, *test.other.Communities...)
+		}
+
+		test.b.MergeCommunitiesFrom(test.other, test.includeLarge)
+		assert.Equal(t, test.expectedCommunities, test.b.Communities, test.name)
+		assert.Equal(t, test.expectedLargeCommunities, test.b.LargeCommunities, test.name)
+
+		if test.other.Communities != nil {
+			assert.Equal(t, otherComs, *test.other.Communities, test.name)
+		}
+	}
+}
+
+func TestConflictingCommunities(t *testing.T) {
+	prefer := uint32(65000<<16 + 100)
+	depref := uint32(65000<<16 + 50)
+	blackhole := uint32(65000<<16 + 666)
+	noExport := uint32(65000<<16 + 1)
+
+	conflicts := [][]uint32{
+		{prefer, depref},
+		{blackhole, noExport},
+	}
+
+	tests := []struct {
+		name     string
+		coms     *types.Communities
+		expected [][]uint32
+	}{
+		{
+			name:     "No communities",
+			expected: [][]uint32{},
+		},
+		{
+			name:     "Both members of a conflict pair",
+			coms:     &types.Communities{prefer, blackhole, depref},
+			expected: [][]uint32{{prefer, depref}},
+		},
+		{
+			name:     "No conflict",
+			coms:     &types.Communities{prefer, blackhole},
+			expected: [][]uint32{},
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			Communities: test.coms,
+		}
+
+		assert.Equal(t, test.expected, p.ConflictingCommunities(conflicts), test.name)
+	}
+}
+
+func TestAgeString(t *testing.T) {
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected string
+	}{
+		{
+			name:     "Less than a day",
+			age:      3*time.Hour + 4*time.Minute + 5*time.Second,
+			expected: "03:04:05",
+		},
+		{
+			name:     "Days",
+			age:      2*24*time.Hour + 3*time.Hour + 10*time.Minute,
+			expected: "2d03h",
+		},
+		{
+			name:     "Weeks",
+			age:      9*24*time.Hour + 3*time.Hour,
+			expected: "1w2d03h",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, formatAge(test.age), test.name)
+	}
+
+	p := &BGPPath{}
+	assert.Equal(t, time.Duration(0), p.Age())
+	assert.Equal(t, "never", p.AgeString())
+
+	p.ReceivedAt = time.Now().Add(-time.Hour - time.Second)
+	assert.True(t, p.Age() > time.Hour)
+	assert.Equal(t, "01:00:01", p.AgeString())
+}
+
+func TestStripConfederationSegments(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASConfedSequence,
+				ASNs: []uint32{65010, 65011},
+			},
+			{
+				Type: types.ASConfedSet,
+				ASNs: []uint32{65012},
+			},
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320, 201701},
+			},
+		},
+		ASPathLen: 2,
+	}
+
+	p.StripConfederationSegments()
+	assert.Equal(t, &types.ASPath{
+		{
+			Type: types.ASSequence,
+			ASNs: []uint32{3320, 201701},
+		},
+	}, p.ASPath)
+	assert.Equal(t, uint16(2), p.ASPathLen)
+}
+
+func TestPrependCapped(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{201701},
+			},
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		p.Prepend(3320, 200)
+	}
+
+	assert.Equal(t, uint16(MaxASPathASNs), p.ASPathLen)
+
+	p.Prepend(3320, 1)
+	assert.Equal(t, uint16(MaxASPathASNs), p.ASPathLen)
+
+	last := (*p.ASPath)[len(*p.ASPath)-1]
+	assert.Equal(t, uint32(201701), last.ASNs[len(last.ASNs)-1])
+}
+
+func TestPrependSplitsFullSegment(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{201701},
+			},
+		},
+	}
+
+	p.Prepend(3320, 600)
+
+	assert.Equal(t, uint16(601), p.ASPathLen)
+	assert.Equal(t, 3, len(*p.ASPath))
+	for _, seg := range *p.ASPath {
+		assert.True(t, len(seg.ASNs) <= types.MaxASNsSegment)
+	}
+
+	assert.Equal(t, types.MaxASNsSegment, len((*p.ASPath)[1].ASNs))
+	assert.Equal(t, uint32(201701), (*p.ASPath)[2].ASNs[len((*p.ASPath)[2].ASNs)-1])
+}
+
+func TestPrependConfedASN(t *testing.T) {
+	p := &BGPPath{
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320, 201701},
+			},
+		},
+		ASPathLen: 2,
+	}
+
+	p.PrependConfedASN(65010)
+	p.PrependConfedASN(65011)
+
+	assert.Equal(t, &types.ASPath{
+		{
+			Type: types.ASConfedSequence,
+			ASNs: []uint32{65011, 65010},
+		},
+		{
+			Type: types.ASSequence,
+			ASNs: []uint32{3320, 201701},
+		},
+	}, p.ASPath)
+	assert.Equal(t, uint16(2), p.ASPathLen)
+
+	p.StripConfederationSegments()
+	assert.Equal(t, "3320 201701", p.ASPath.String())
+}
+
+func TestASPathEditDistance(t *testing.T) {
+	newPath := func(asns ...uint32) *BGPPath {
+		return &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		a        *BGPPath
+		b        *BGPPath
+		expected int
+	}{
+		{
+			name:     "Equal",
+			a:        newPath(3320, 201701),
+			b:        newPath(3320, 201701),
+			expected: 0,
+		},
+		{
+			name:     "Single prepend",
+			a:        newPath(3320, 201701),
+			b:        newPath(3320, 201701, 201701),
+			expected: 1,
+		},
+		{
+			name:     "Disjoint paths",
+			a:        newPath(3320, 6939, 201701),
+			b:        newPath(174, 1299, 65001),
+			expected: 3,
+		},
+		{
+			name:     "Empty path",
+			a:        &BGPPath{},
+			b:        newPath(174, 1299),
+			expected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.a.ASPathEditDistance(test.b), test.name)
+		assert.Equal(t, test.expected, test.b.ASPathEditDistance(test.a), test.name)
+	}
+}
+
+func TestExtendedCommunitiesProtoRoundTrip(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4(0).Ptr(),
+			Source:  bnet.IPv4(0).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+		ExtendedCommunities: &[]types.ExtendedCommunity{
+			{
+				Type:    types.ExtendedCommunityTypeTwoOctetAS,
+				SubType: types.ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+			{
+				Type:    0x03,
+				SubType: 0x0b,
+				Value:   [6]byte{0, 0, 0, 0, 0, 100},
+			},
+		},
+	}
+
+	res := BGPPathFromProtoBGPPath(p.ToProto(), false)
+	assert.Equal(t, p.ExtendedCommunities, res.ExtendedCommunities)
+	assert.Equal(t, "(rt,65000,1) 0x030b000000000064", res.ExtendedCommunitiesString())
+	assert.Equal(t, p.ComputeHash(), res.ComputeHash())
+}
+
+func TestSelectAIGP(t *testing.T) {
+	newPath := func(med uint32, aigp uint64, aigpPresent bool) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref:   100,
+				MED:         med,
+				AIGP:        aigp,
+				AIGPPresent: aigpPresent,
+				Source:      bnet.IPv4(0).Ptr(),
+				NextHop:     bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		b        *BGPPath
+		c        *BGPPath
+		expected int8
+	}{
+		{
+			name:     "Lower AIGP wins over lower MED",
+			b:        newPath(100, 10, true),
+			c:        newPath(10, 20, true),
+			expected: 1,
+		},
+		{
+			name:     "Higher AIGP loses",
+			b:        newPath(10, 20, true),
+			c:        newPath(100, 10, true),
+			expected: -1,
+		},
+		{
+			name:     "Equal AIGP falls through to MED",
+			b:        newPath(100, 10, true),
+			c:        newPath(10, 10, true),
+			expected: -1,
+		},
+		{
+			name:     "AIGP on one path only is ignored",
+			b:        newPath(100, 10, true),
+			c:        newPath(10, 0, false),
+			expected: -1,
+		},
+		{
+			name:     "AIGP on the other path only is ignored",
+			b:        newPath(10, 0, false),
+			c:        newPath(100, 10, true),
+			expected: 1,
+		},
+		{
+			name:     "No AIGP on both paths compares MED",
+			b:        newPath(10, 0, false),
+			c:        newPath(100, 0, false),
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.b.Select(test.c), test.name)
+	}
+}
+
+func TestSelectAIGPOnOnePathOnly(t *testing.T) {
+	newPath := func(med uint32, igpMetric uint32, aigpPresent bool) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref:   100,
+				MED:         med,
+				IGPMetric:   igpMetric,
+				AIGP:        1,
+				AIGPPresent: aigpPresent,
+				Source:      bnet.IPv4(0).Ptr(),
+				NextHop:     bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	withAIGP := newPath(20, 10, true)
+	withoutAIGP := newPath(10, 10, false)
+
+	// MED decides as if neither path carried AIGP
+	assert.Equal(t, int8(-1), withAIGP.Select(withoutAIGP))
+	assert.Equal(t, int8(1), withoutAIGP.Select(withAIGP))
+	assert.True(t, withAIGP.betterECMP(withoutAIGP))
+	assert.False(t, withoutAIGP.betterECMP(withAIGP))
+
+	// Same for the IGP metric
+	withAIGP = newPath(10, 20, true)
+	assert.Equal(t, int8(-1), withAIGP.Select(withoutAIGP))
+	assert.True(t, withAIGP.betterECMP(withoutAIGP))
+
+	assert.True(t, newPath(10, 10, true).ECMP(newPath(10, 10, false)))
+	assert.False(t, newPath(10, 10, true).ECMP(newPath(20, 10, false)))
+}
+
+func TestAIGPProtoRoundTrip(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:     bnet.IPv4(0).Ptr(),
+			Source:      bnet.IPv4(0).Ptr(),
+			AIGP:        1 << 40,
+			AIGPPresent: true,
+		},
+		ASPath: &types.ASPath{},
+	}
+
+	res := BGPPathFromProtoBGPPath(p.ToProto(), false)
+	assert.Equal(t, uint64(1<<40), res.BGPPathA.AIGP)
+	assert.True(t, res.BGPPathA.AIGPPresent)
+	assert.Equal(t, p.ComputeHash(), res.ComputeHash())
+
+	q := p.Copy()
+	q.BGPPathA = &BGPPathA{
+		NextHop: bnet.IPv4(0).Ptr(),
+		Source:  bnet.IPv4(0).Ptr(),
+	}
+	assert.NotEqual(t, p.ComputeHash(), q.ComputeHash())
+}
+
+func TestNextHopLinkLocalProtoRoundTrip(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop:          bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 1).Ptr(),
+			NextHopLinkLocal: bnet.IPv6FromBlocks(0xfe80, 0, 0, 0, 0, 0, 0, 1).Ptr(),
+			Source:           bnet.IPv6(0, 0).Ptr(),
+		},
+		ASPath: &types.ASPath{},
+	}
+
+	res := BGPPathFromProtoBGPPath(p.ToProto(), false)
+	assert.Equal(t, p.BGPPathA.NextHopLinkLocal, res.BGPPathA.NextHopLinkLocal)
+
+	p.BGPPathA.NextHopLinkLocal = nil
+	res = BGPPathFromProtoBGPPath(p.ToProto(), true)
+	assert.Nil(t, res.BGPPathA.NextHopLinkLocal)
+}
@@ -0,0 +1,66 @@
+package route
+
+import (
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// Relationship is the business relationship between two ASes
+type Relationship uint8
+
+const (
+	// RelationshipUnknown is used if the relationship is not known
+	RelationshipUnknown Relationship = iota
+	// RelationshipCustomer means the other AS is a customer
+	RelationshipCustomer
+	// RelationshipPeer means the other AS is a peer
+	RelationshipPeer
+	// RelationshipProvider means the other AS is a provider
+	RelationshipProvider
+)
+
+// ViolatesValleyFree checks if the AS path contains a valley, i.e. a path that was propagated
+// to a provider or peer after it has been propagated to a customer or peer before.
+// rel(a, b) returns the relationship of b from the perspective of a, e.g. RelationshipProvider if b is a provider of a.
+// Hops with unknown relationship are ignored. AS_SETs interrupt the adjacency of ASNs.
+func (b *BGPPath) ViolatesValleyFree(rel func(a, b uint32) Relationship) bool {
+	if b.ASPath == nil {
+		return false
+	}
+
+	// Walk the path in propagation direction, starting at the origin
+	descending := false
+	prev := uint32(0)
+	havePrev := false
+	for i := len(*b.ASPath) - 1; i >= 0; i-- {
+		seg := (*b.ASPath)[i]
+		if seg.Type != types.ASSequence {
+			havePrev = false
+			continue
+		}
+
+		for j := len(seg.ASNs) - 1; j >= 0; j-- {
+			asn := seg.ASNs[j]
+			if havePrev && asn != prev {
+				switch rel(prev, asn) {
+				case RelationshipProvider:
+					if descending {
+						return true
+					}
+				case RelationshipPeer:
+					if descending {
+						return true
+					}
+
+					descending = true
+				case RelationshipCustomer:
+					descending = true
+				}
+			}
+
+			prev = asn
+			havePrev = true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViolatesValleyFree(t *testing.T) {
+	// 1 and 2 are providers of 10, 1 and 2 are peers, 10 is a provider of 100 and 200
+	providers := map[uint32][]uint32{
+		10:  {1, 2},
+		100: {10},
+		200: {10},
+	}
+
+	rel := func(a, b uint32) Relationship {
+		for _, p := range providers[a] {
+			if p == b {
+				return RelationshipProvider
+			}
+		}
+
+		for _, c := range providers[b] {
+			if c == a {
+				return RelationshipCustomer
+			}
+		}
+
+		if (a == 1 && b == 2) || (a == 2 && b == 1) {
+			return RelationshipPeer
+		}
+
+		return RelationshipUnknown
+	}
+
+	tests := []struct {
+		name     string
+		asPath   *types.ASPath
+		expected bool
+	}{
+		{
+			name: "Up, across and down",
+			asPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{200, 10, 2, 1, 10, 100},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Up and down with prepends",
+			asPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{200, 10, 10, 100, 100},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Valley",
+			asPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{2, 10, 1},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "Two peer hops",
+			asPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{1, 2, 1},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "No adjacency across AS set",
+			asPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{1},
+				},
+				{
+					Type: types.ASSet,
+					ASNs: []uint32{300},
+				},
+				{
+					Type: types.ASSequence,
+					ASNs: []uint32{10, 2},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		p := &BGPPath{
+			ASPath: test.asPath,
+		}
+
+		assert.Equal(t, test.expected, p.ViolatesValleyFree(rel), test.name)
+	}
+
+	assert.False(t, (&BGPPath{}).ViolatesValleyFree(rel))
+}
@@ -0,0 +1,298 @@
+package route
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+)
+
+// CommunityIntersection returns the sorted communities carried by all of paths
+func CommunityIntersection(paths []*BGPPath) []uint32 {
+	if len(paths) == 0 {
+		return []uint32{}
+	}
+
+	counts := make(map[uint32]int)
+	for _, p := range paths {
+		if p.Communities == nil {
+			return []uint32{}
+		}
+
+		seen := make(map[uint32]struct{}, len(*p.Communities))
+		for _, com := range *p.Communities {
+			if _, ok := seen[com]; ok {
+				continue
+			}
+
+			seen[com] = struct{}{}
+			counts[com]++
+		}
+	}
+
+	ret := make([]uint32, 0)
+	for com, n := range counts {
+		if n == len(paths) {
+			ret = append(ret, com)
+		}
+	}
+
+	sortUint32s(ret)
+	return ret
+}
+
+// CommunityUnion returns the sorted and deduplicated communities carried by any of paths
+func CommunityUnion(paths []*BGPPath) []uint32 {
+	seen := make(map[uint32]struct{})
+	ret := make([]uint32, 0)
+	for _, p := range paths {
+		if p.Communities == nil {
+			continue
+		}
+
+		for _, com := range *p.Communities {
+			if _, ok := seen[com]; ok {
+				continue
+			}
+
+			seen[com] = struct{}{}
+			ret = append(ret, com)
+		}
+	}
+
+	sortUint32s(ret)
+	return ret
+}
+
+// OriginASSet returns the sorted distinct origin ASNs of paths. More than one origin AS for the
+// paths of a single prefix indicates a multiple origin AS (MOAS) conflict.
+func OriginASSet(paths []*BGPPath) []uint32 {
+	seen := make(map[uint32]struct{})
+	ret := make([]uint32, 0)
+	for _, p := range paths {
+		asn, ok := p.OriginAS()
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[asn]; ok {
+			continue
+		}
+
+		seen[asn] = struct{}{}
+		ret = append(ret, asn)
+	}
+
+	sortUint32s(ret)
+	return ret
+}
+
+// DuplicatePathIDs returns the sorted path identifiers used by more than one of paths
+func DuplicatePathIDs(paths []*BGPPath) []uint32 {
+	counts := make(map[uint32]int, len(paths))
+	for _, p := range paths {
+		counts[p.PathIdentifier]++
+	}
+
+	ret := make([]uint32, 0)
+	for id, n := range counts {
+		if n > 1 {
+			ret = append(ret, id)
+		}
+	}
+
+	sortUint32s(ret)
+	return ret
+}
+
+// WithdrawnPathIDs returns the sorted and deduplicated path identifiers of old not used by any path of new
+func WithdrawnPathIDs(old, new []*BGPPath) []uint32 {
+	present := make(map[uint32]struct{}, len(new))
+	for _, p := range new {
+		present[p.PathIdentifier] = struct{}{}
+	}
+
+	ret := make([]uint32, 0)
+	for _, p := range old {
+		if _, ok := present[p.PathIdentifier]; ok {
+			continue
+		}
+
+		present[p.PathIdentifier] = struct{}{}
+		ret = append(ret, p.PathIdentifier)
+	}
+
+	sortUint32s(ret)
+	return ret
+}
+
+// DiffPathSets compares two sets of paths keyed by prefix and path identifier. It returns the sorted keys
+// only present in new (added), only present in old (removed) and present in both but with different attributes (changed).
+func DiffPathSets(old, new map[string]*BGPPath) (added, removed, changed []string) {
+	added = make([]string, 0)
+	removed = make([]string, 0)
+	changed = make([]string, 0)
+
+	for k, n := range new {
+		o, ok := old[k]
+		if !ok {
+			added = append(added, k)
+			continue
+		}
+
+		if !o.AttributesEqual(n) {
+			changed = append(changed, k)
+		}
+	}
+
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	return added, removed, changed
+}
+
+// DistinctNextHops returns paths without the paths whose next hop equals the next hop of a preceding path.
+// Paths without a next hop are considered to share the same (nil) next hop.
+func DistinctNextHops(paths []*BGPPath) []*BGPPath {
+	ret := make([]*BGPPath, 0, len(paths))
+	for _, p := range paths {
+		dup := false
+		for _, q := range ret {
+			if sameNextHop(p.nextHop(), q.nextHop()) {
+				dup = true
+				break
+			}
+		}
+
+		if !dup {
+			ret = append(ret, p)
+		}
+	}
+
+	return ret
+}
+
+func (b *BGPPath) nextHop() *bnet.IP {
+	if b.BGPPathA == nil {
+		return nil
+	}
+
+	return b.BGPPathA.NextHop
+}
+
+func sameNextHop(a, b *bnet.IP) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Compare(b) == 0 && a.IsIPv4() == b.IsIPv4()
+}
+
+// ContributingPrefixes returns the candidates strictly more specific than aggregate
+func ContributingPrefixes(aggregate bnet.Prefix, candidates []bnet.Prefix) []bnet.Prefix {
+	ret := make([]bnet.Prefix, 0)
+	for _, c := range candidates {
+		if c.Addr().IsIPv4() != aggregate.Addr().IsIPv4() {
+			continue
+		}
+
+		if c.Pfxlen() <= aggregate.Pfxlen() || !aggregate.Contains(&c) {
+			continue
+		}
+
+		ret = append(ret, c)
+	}
+
+	return ret
+}
+
+// CoveringAggregate returns the most specific of aggregates strictly less specific than and containing p
+func CoveringAggregate(p bnet.Prefix, aggregates []bnet.Prefix) (bnet.Prefix, bool) {
+	var ret bnet.Prefix
+	found := false
+	for _, a := range aggregates {
+		if a.Addr().IsIPv4() != p.Addr().IsIPv4() {
+			continue
+		}
+
+		if a.Pfxlen() >= p.Pfxlen() || !a.Contains(&p) {
+			continue
+		}
+
+		if !found || a.Pfxlen() > ret.Pfxlen() {
+			ret = a
+			found = true
+		}
+	}
+
+	return ret, found
+}
+
+func sortUint32s(x []uint32) {
+	sort.Slice(x, func(i, j int) bool {
+		return x[i] < x[j]
+	})
+}
+
+// LessBGPPath reports whether a is preferred over b according to Select. It can be used with sort.Slice
+// to sort paths into best path order. The path identifier makes the order of otherwise equal paths deterministic.
+func LessBGPPath(a, b *BGPPath) bool {
+	return a.Select(b) > 0
+}
+
+// BestExternal returns the best path according to Select among the paths learned via eBGP (advertise best external).
+// nil is returned if there is no eBGP path.
+func BestExternal(paths []*BGPPath) *BGPPath {
+	var best *BGPPath
+	for _, p := range paths {
+		if !p.BGPPathA.EBGP {
+			continue
+		}
+
+		if best == nil || p.Select(best) > 0 {
+			best = p
+		}
+	}
+
+	return best
+}
+
+// ComputeHashes computes the hashes of paths using a worker pool of one goroutine per CPU.
+// The hash of paths[i] is returned at index i.
+func ComputeHashes(paths []*BGPPath) []string {
+	ret := make([]string, len(paths))
+
+	workers := runtime.NumCPU()
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	idx := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range idx {
+				ret[j] = paths[j].ComputeHash()
+			}
+		}()
+	}
+
+	for i := range paths {
+		idx <- i
+	}
+
+	close(idx)
+	wg.Wait()
+
+	return ret
+}
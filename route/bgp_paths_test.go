@@ -0,0 +1,407 @@
+package route
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommunityIntersectionAndUnion(t *testing.T) {
+	tests := []struct {
+		name                 string
+		paths                []*BGPPath
+		expectedIntersection []uint32
+		expectedUnion        []uint32
+	}{
+		{
+			name:                 "No paths",
+			expectedIntersection: []uint32{},
+			expectedUnion:        []uint32{},
+		},
+		{
+			name: "Three paths sharing one community",
+			paths: []*BGPPath{
+				{
+					Communities: &types.Communities{300, 100, 100},
+				},
+				{
+					Communities: &types.Communities{200, 100},
+				},
+				{
+					Communities: &types.Communities{100, 400, 300},
+				},
+			},
+			expectedIntersection: []uint32{100},
+			expectedUnion:        []uint32{100, 200, 300, 400},
+		},
+		{
+			name: "One path without communities",
+			paths: []*BGPPath{
+				{
+					Communities: &types.Communities{100},
+				},
+				{},
+			},
+			expectedIntersection: []uint32{},
+			expectedUnion:        []uint32{100},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expectedIntersection, CommunityIntersection(test.paths), test.name)
+		assert.Equal(t, test.expectedUnion, CommunityUnion(test.paths), test.name)
+	}
+}
+
+func TestOriginASSet(t *testing.T) {
+	newPath := func(asns ...uint32) *BGPPath {
+		return &BGPPath{
+			ASPath: &types.ASPath{
+				{
+					Type: types.ASSequence,
+					ASNs: asns,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		paths    []*BGPPath
+		expected []uint32
+	}{
+		{
+			name: "Single origin",
+			paths: []*BGPPath{
+				newPath(3320, 15169),
+				newPath(6939, 15169),
+			},
+			expected: []uint32{15169},
+		},
+		{
+			name: "Multiple origins",
+			paths: []*BGPPath{
+				newPath(3320, 15169),
+				newPath(6939, 13335),
+				newPath(174, 15169),
+			},
+			expected: []uint32{13335, 15169},
+		},
+		{
+			name: "Paths without origin AS are ignored",
+			paths: []*BGPPath{
+				{},
+				{
+					ASPath: &types.ASPath{
+						{
+							Type: types.ASSequence,
+							ASNs: []uint32{3320},
+						},
+						{
+							Type: types.ASSet,
+							ASNs: []uint32{100, 200},
+						},
+					},
+				},
+				newPath(3320, 201701),
+			},
+			expected: []uint32{201701},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, OriginASSet(test.paths), test.name)
+	}
+}
+
+func TestDuplicatePathIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []*BGPPath
+		expected []uint32
+	}{
+		{
+			name: "Unique path IDs",
+			paths: []*BGPPath{
+				{PathIdentifier: 1},
+				{PathIdentifier: 2},
+			},
+			expected: []uint32{},
+		},
+		{
+			name: "Two paths sharing an ID",
+			paths: []*BGPPath{
+				{PathIdentifier: 1},
+				{PathIdentifier: 2},
+				{PathIdentifier: 1},
+			},
+			expected: []uint32{1},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, DuplicatePathIDs(test.paths), test.name)
+	}
+}
+
+func TestWithdrawnPathIDs(t *testing.T) {
+	newPaths := func(ids ...uint32) []*BGPPath {
+		ret := make([]*BGPPath, 0, len(ids))
+		for _, id := range ids {
+			ret = append(ret, &BGPPath{PathIdentifier: id})
+		}
+
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		old      []*BGPPath
+		new      []*BGPPath
+		expected []uint32
+	}{
+		{
+			name:     "Paths added and removed",
+			old:      newPaths(5, 1, 2, 3),
+			new:      newPaths(2, 4),
+			expected: []uint32{1, 3, 5},
+		},
+		{
+			name:     "Duplicate IDs",
+			old:      newPaths(1, 1),
+			new:      newPaths(),
+			expected: []uint32{1},
+		},
+		{
+			name:     "Nothing withdrawn",
+			old:      newPaths(1),
+			new:      newPaths(1, 2),
+			expected: []uint32{},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, WithdrawnPathIDs(test.old, test.new), test.name)
+	}
+}
+
+func TestDiffPathSets(t *testing.T) {
+	newPath := func(localPref uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: localPref,
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				Source:    bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	old := map[string]*BGPPath{
+		"10.0.0.0/8:0":     newPath(100),
+		"10.0.0.0/8:1":     newPath(100),
+		"192.168.0.0/16:0": newPath(100),
+		"172.16.0.0/12:0":  newPath(100),
+	}
+
+	new := map[string]*BGPPath{
+		"10.0.0.0/8:0":     newPath(100),
+		"10.0.0.0/8:1":     newPath(200),
+		"172.16.0.0/12:0":  newPath(100),
+		"100.64.0.0/10:0":  newPath(100),
+		"100.64.0.0/10:23": newPath(100),
+	}
+
+	added, removed, changed := DiffPathSets(old, new)
+	assert.Equal(t, []string{"100.64.0.0/10:0", "100.64.0.0/10:23"}, added)
+	assert.Equal(t, []string{"192.168.0.0/16:0"}, removed)
+	assert.Equal(t, []string{"10.0.0.0/8:1"}, changed)
+}
+
+func TestDistinctNextHops(t *testing.T) {
+	newPath := func(pathID uint32, nextHop bnet.IP) *BGPPath {
+		return &BGPPath{
+			PathIdentifier: pathID,
+			BGPPathA: &BGPPathA{
+				NextHop: nextHop.Ptr(),
+			},
+		}
+	}
+
+	paths := []*BGPPath{
+		newPath(1, bnet.IPv4FromOctets(10, 0, 0, 1)),
+		newPath(2, bnet.IPv4FromOctets(10, 0, 0, 2)),
+		newPath(3, bnet.IPv4FromOctets(10, 0, 0, 1)),
+	}
+
+	assert.Equal(t, []*BGPPath{paths[0], paths[1]}, DistinctNextHops(paths))
+	assert.Equal(t, []*BGPPath{}, DistinctNextHops(nil))
+
+	noNextHop := []*BGPPath{
+		{PathIdentifier: 1, BGPPathA: &BGPPathA{}},
+		newPath(2, bnet.IPv4FromOctets(10, 0, 0, 1)),
+		{PathIdentifier: 3, BGPPathA: &BGPPathA{}},
+		{PathIdentifier: 4},
+	}
+	assert.Equal(t, []*BGPPath{noNextHop[0], noNextHop[1]}, DistinctNextHops(noNextHop))
+}
+
+func TestContributingPrefixes(t *testing.T) {
+	aggregate := bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 0, 0), 16)
+	candidates := []bnet.Prefix{
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 1, 0), 24),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 2, 1, 0), 24),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 255, 0), 24),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 0, 0), 16),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 0, 0, 0), 8),
+		bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32),
+	}
+
+	assert.Equal(t, []bnet.Prefix{
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 1, 0), 24),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 255, 0), 24),
+	}, ContributingPrefixes(aggregate, candidates))
+}
+
+func TestCoveringAggregate(t *testing.T) {
+	aggregates := []bnet.Prefix{
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 0, 0, 0), 8),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 0, 0), 16),
+		bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 1, 0), 24),
+		bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32),
+	}
+
+	tests := []struct {
+		name          string
+		pfx           bnet.Prefix
+		expected      bnet.Prefix
+		expectedFound bool
+	}{
+		{
+			name:          "Most specific cover",
+			pfx:           bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 2, 0), 24),
+			expected:      bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 0, 0), 16),
+			expectedFound: true,
+		},
+		{
+			name:          "Aggregate itself is covered by the next less specific",
+			pfx:           bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 1, 0), 24),
+			expected:      bnet.NewPfx(bnet.IPv4FromOctets(10, 1, 0, 0), 16),
+			expectedFound: true,
+		},
+		{
+			name:          "IPv6",
+			pfx:           bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 1, 0, 0, 0, 0, 0), 48),
+			expected:      bnet.NewPfx(bnet.IPv6FromBlocks(0x2001, 0xdb8, 0, 0, 0, 0, 0, 0), 32),
+			expectedFound: true,
+		},
+		{
+			name: "Not covered",
+			pfx:  bnet.NewPfx(bnet.IPv4FromOctets(192, 168, 0, 0), 24),
+		},
+	}
+
+	for _, test := range tests {
+		res, found := CoveringAggregate(test.pfx, aggregates)
+		assert.Equal(t, test.expectedFound, found, test.name)
+		assert.Equal(t, test.expected, res, test.name)
+	}
+}
+
+func TestLessBGPPath(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	paths := make([]*BGPPath, 50)
+	for i := range paths {
+		paths[i] = randomBGPPath(r)
+		paths[i].PathIdentifier = uint32(r.Intn(5))
+	}
+
+	// Repeatedly extract the best remaining path
+	remaining := append([]*BGPPath{}, paths...)
+	extracted := make([]*BGPPath, 0, len(paths))
+	for len(remaining) > 0 {
+		best := 0
+		for i := range remaining {
+			if remaining[i].Select(remaining[best]) > 0 {
+				best = i
+			}
+		}
+
+		extracted = append(extracted, remaining[best])
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return LessBGPPath(paths[i], paths[j])
+	})
+
+	for i := range paths {
+		assert.Equal(t, int8(0), paths[i].Select(extracted[i]), "position %d", i)
+	}
+}
+
+func TestComputeHashes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	paths := make([]*BGPPath, 200)
+	for i := range paths {
+		paths[i] = randomBGPPath(r)
+	}
+
+	hashes := ComputeHashes(paths)
+	assert.Equal(t, len(paths), len(hashes))
+	for i := range paths {
+		assert.Equal(t, paths[i].ComputeHash(), hashes[i], "position %d", i)
+	}
+
+	assert.Equal(t, []string{}, ComputeHashes(nil))
+}
+
+func TestBestExternal(t *testing.T) {
+	newPath := func(localPref uint32, ebgp bool, med uint32) *BGPPath {
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: localPref,
+				EBGP:      ebgp,
+				MED:       med,
+				Source:    bnet.IPv4(0).Ptr(),
+				NextHop:   bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+		}
+	}
+
+	ibgp := newPath(200, false, 0)
+	ebgpWorse := newPath(100, true, 20)
+	ebgpBetter := newPath(100, true, 10)
+
+	tests := []struct {
+		name     string
+		paths    []*BGPPath
+		expected *BGPPath
+	}{
+		{
+			name:     "Overall best is iBGP",
+			paths:    []*BGPPath{ebgpWorse, ibgp, ebgpBetter},
+			expected: ebgpBetter,
+		},
+		{
+			name:     "No eBGP path",
+			paths:    []*BGPPath{ibgp},
+			expected: nil,
+		},
+		{
+			name:     "Empty",
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		assert.True(t, test.expected == BestExternal(test.paths), test.name)
+	}
+}
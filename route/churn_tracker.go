@@ -0,0 +1,153 @@
+package route
+
+import (
+	"sync"
+	"time"
+)
+
+// ChangeClass classifies the change between two versions of a path
+type ChangeClass uint8
+
+const (
+	// ChangeNone means no change was recorded (yet)
+	ChangeNone ChangeClass = iota
+	// ChangeInitial is the first version of a path
+	ChangeInitial
+	// ChangeNextHop means the next hop changed
+	ChangeNextHop
+	// ChangeASPath means the AS path changed (but not the next hop)
+	ChangeASPath
+	// ChangeCommunities means communities or large communities changed (but not the next hop or AS path)
+	ChangeCommunities
+	// ChangeOther means any other attribute changed
+	ChangeOther
+)
+
+// String returns the name of the change class
+func (c ChangeClass) String() string {
+	switch c {
+	case ChangeNone:
+		return "none"
+	case ChangeInitial:
+		return "initial"
+	case ChangeNextHop:
+		return "next-hop"
+	case ChangeASPath:
+		return "as-path"
+	case ChangeCommunities:
+		return "communities"
+	case ChangeOther:
+		return "other"
+	}
+
+	return "unknown"
+}
+
+// ChurnStats are the statistics of a ChurnTracker
+type ChurnStats struct {
+	// Updates is the number of versions recorded
+	Updates uint64
+	// Changes is the number of versions that differ from their predecessor
+	Changes uint64
+	// Flaps is the number of changes returning to the version before the previous one (A -> B -> A)
+	Flaps uint64
+	// LastChange is the classification of the last change
+	LastChange ChangeClass
+	// LastChangeAt is the time of the last change
+	LastChangeAt time.Time
+	// Recent are the most recent changes (at most maxChurnEvents), oldest first. See ShouldDamp.
+	Recent []ChurnEvent
+}
+
+// ChurnEvent is a change of a path
+type ChurnEvent struct {
+	// At is the time of the change
+	At time.Time
+	// Flap is set if the change returned to the version before the previous one
+	Flap bool
+}
+
+const maxChurnEvents = 32
+
+// ChurnTracker records successive versions of a path (a prefix and path identifier) to quantify churn
+type ChurnTracker struct {
+	mu       sync.Mutex
+	current  *BGPPath
+	previous *BGPPath
+	stats    ChurnStats
+	now      func() time.Time
+}
+
+// NewChurnTracker creates a new ChurnTracker
+func NewChurnTracker() *ChurnTracker {
+	return &ChurnTracker{
+		now: time.Now,
+	}
+}
+
+// Record records a new version of the path
+func (c *ChurnTracker) Record(p *BGPPath) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Updates++
+	if c.current == nil {
+		c.current = p
+		c.stats.LastChange = ChangeInitial
+		c.stats.LastChangeAt = c.now()
+		return
+	}
+
+	if c.current.AttributesEqual(p) {
+		return
+	}
+
+	now := c.now()
+	flap := c.previous != nil && c.previous.AttributesEqual(p)
+
+	c.stats.Changes++
+	if flap {
+		c.stats.Flaps++
+	}
+
+	if len(c.stats.Recent) == maxChurnEvents {
+		copy(c.stats.Recent, c.stats.Recent[1:])
+		c.stats.Recent = c.stats.Recent[:maxChurnEvents-1]
+	}
+
+	c.stats.Recent = append(c.stats.Recent, ChurnEvent{
+		At:   now,
+		Flap: flap,
+	})
+
+	c.stats.LastChange = classifyChange(c.current, p)
+	c.stats.LastChangeAt = now
+	c.previous = c.current
+	c.current = p
+}
+
+// Stats gets the statistics of the tracker
+func (c *ChurnTracker) Stats() ChurnStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := c.stats
+	ret.Recent = append([]ChurnEvent(nil), c.stats.Recent...)
+	return ret
+}
+
+func classifyChange(old, new *BGPPath) ChangeClass {
+	if old.BGPPathA.NextHop.Compare(new.BGPPathA.NextHop) != 0 {
+		return ChangeNextHop
+	}
+
+	if !old.ASPath.Compare(new.ASPath) {
+		return ChangeASPath
+	}
+
+	if !old.compareCommunities(new) || !old.compareLargeCommunities(new) {
+		return ChangeCommunities
+	}
+
+	return ChangeOther
+}
@@ -0,0 +1,104 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func churnPath(nextHop uint8, localPref uint32, coms ...uint32) *BGPPath {
+	c := types.Communities(coms)
+	return &BGPPath{
+		BGPPathA: &BGPPathA{
+			LocalPref: localPref,
+			NextHop:   bnet.IPv4FromOctets(10, 0, 0, nextHop).Ptr(),
+			Source:    bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+		ASPath:      &types.ASPath{},
+		Communities: &c,
+	}
+}
+
+func TestChurnTracker(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	c := NewChurnTracker()
+	c.now = func() time.Time {
+		ts = ts.Add(time.Second)
+		return ts
+	}
+
+	assert.Equal(t, ChurnStats{}, c.Stats())
+
+	a := churnPath(1, 100)
+	b := churnPath(2, 100)
+
+	c.Record(a)
+	c.Record(a)
+	assert.Equal(t, ChurnStats{
+		Updates:      2,
+		LastChange:   ChangeInitial,
+		LastChangeAt: time.Unix(1700000001, 0),
+	}, c.Stats())
+
+	c.Record(b)
+	c.Record(a)
+	c.Record(b)
+	c.Record(a)
+	assert.Equal(t, ChurnStats{
+		Updates:      6,
+		Changes:      4,
+		Flaps:        3,
+		LastChange:   ChangeNextHop,
+		LastChangeAt: time.Unix(1700000005, 0),
+		Recent: []ChurnEvent{
+			{At: time.Unix(1700000002, 0)},
+			{At: time.Unix(1700000003, 0), Flap: true},
+			{At: time.Unix(1700000004, 0), Flap: true},
+			{At: time.Unix(1700000005, 0), Flap: true},
+		},
+	}, c.Stats())
+
+	for i := 0; i < maxChurnEvents; i++ {
+		c.Record(b)
+		c.Record(a)
+	}
+
+	stats := c.Stats()
+	assert.Len(t, stats.Recent, maxChurnEvents)
+	assert.Equal(t, stats.LastChangeAt, stats.Recent[maxChurnEvents-1].At)
+}
+
+func TestClassifyChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      *BGPPath
+		new      *BGPPath
+		expected ChangeClass
+	}{
+		{
+			name:     "Next hop",
+			old:      churnPath(1, 100),
+			new:      churnPath(2, 200),
+			expected: ChangeNextHop,
+		},
+		{
+			name:     "Communities",
+			old:      churnPath(1, 100, 1),
+			new:      churnPath(1, 100, 2),
+			expected: ChangeCommunities,
+		},
+		{
+			name:     "Other",
+			old:      churnPath(1, 100),
+			new:      churnPath(1, 200),
+			expected: ChangeOther,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, classifyChange(test.old, test.new), test.name)
+	}
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// CommunityList is a list of communities, large communities and extended communities
+type CommunityList struct {
+	Communities         []uint32
+	LargeCommunities    []types.LargeCommunity
+	ExtendedCommunities []types.ExtendedCommunity
+}
+
+// ContainsCommunity checks if com is part of the list
+func (l CommunityList) ContainsCommunity(com uint32) bool {
+	for _, x := range l.Communities {
+		if x == com {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContainsExtendedCommunity checks if com is part of the list
+func (l CommunityList) ContainsExtendedCommunity(com types.ExtendedCommunity) bool {
+	for _, x := range l.ExtendedCommunities {
+		if x == com {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContainsLargeCommunity checks if com is part of the list
+func (l CommunityList) ContainsLargeCommunity(com types.LargeCommunity) bool {
+	for _, x := range l.LargeCommunities {
+		if x == com {
+			return true
+		}
+	}
+
+	return false
+}
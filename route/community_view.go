@@ -0,0 +1,70 @@
+package route
+
+import (
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// CommunityKind is the kind of community of a CommunityView
+type CommunityKind uint8
+
+const (
+	// CommunityKindStandard is a standard community (RFC1997)
+	CommunityKindStandard CommunityKind = iota
+	// CommunityKindLarge is a large community (RFC8092)
+	CommunityKindLarge
+	// CommunityKindExtended is an extended community (RFC4360)
+	CommunityKindExtended
+)
+
+// CommunityView is a uniform view on a standard, large or extended community. Only the field matching Kind is set.
+type CommunityView struct {
+	Kind     CommunityKind
+	Standard uint32
+	Large    types.LargeCommunity
+	Extended types.ExtendedCommunity
+}
+
+// String returns the human readable representation of the community
+func (v CommunityView) String() string {
+	switch v.Kind {
+	case CommunityKindStandard:
+		return types.CommunityStringForUint32(v.Standard)
+	case CommunityKindLarge:
+		return v.Large.String()
+	case CommunityKindExtended:
+		return v.Extended.String()
+	}
+
+	return ""
+}
+
+// AllCommunities returns the standard, large and extended communities of the path in this order
+func (b *BGPPath) AllCommunities() []CommunityView {
+	ret := make([]CommunityView, 0)
+	if b.Communities != nil {
+		for _, com := range *b.Communities {
+			ret = append(ret, CommunityView{
+				Kind:     CommunityKindStandard,
+				Standard: com,
+			})
+		}
+	}
+
+	if b.LargeCommunities != nil {
+		for _, com := range *b.LargeCommunities {
+			ret = append(ret, CommunityView{
+				Kind:  CommunityKindLarge,
+				Large: com,
+			})
+		}
+	}
+
+	for _, com := range b.extCommunities() {
+		ret = append(ret, CommunityView{
+			Kind:     CommunityKindExtended,
+			Extended: com,
+		})
+	}
+
+	return ret
+}
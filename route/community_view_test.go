@@ -0,0 +1,45 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllCommunities(t *testing.T) {
+	p := &BGPPath{
+		Communities: &types.Communities{65000<<16 | 100},
+		LargeCommunities: &types.LargeCommunities{
+			{GlobalAdministrator: 65000, DataPart1: 1, DataPart2: 2},
+		},
+		UnknownAttributes: []types.UnknownPathAttribute{
+			{
+				Optional:   true,
+				Transitive: true,
+				TypeCode:   types.ExtendedCommunitiesAttr,
+				Value:      []byte{0x03, 0x0b, 0, 0, 0, 0, 0, 10},
+			},
+		},
+		ExtendedCommunities: &[]types.ExtendedCommunity{
+			{
+				Type:    types.ExtendedCommunityTypeTwoOctetAS,
+				SubType: types.ExtendedCommunitySubTypeRouteTarget,
+				Value:   [6]byte{0xfd, 0xe8, 0, 0, 0, 1},
+			},
+		},
+	}
+
+	views := p.AllCommunities()
+
+	res := make([]string, 0, len(views))
+	for _, v := range views {
+		res = append(res, v.String())
+	}
+
+	assert.Equal(t, []string{"(65000,100)", "(65000,1,2)", "(rt,65000,1)", "0x030b00000000000a"}, res)
+	assert.Equal(t, CommunityKindExtended, views[2].Kind)
+	assert.Equal(t, (*p.ExtendedCommunities)[0], views[2].Extended)
+
+	assert.Equal(t, []CommunityView{}, (&BGPPath{}).AllCommunities())
+}
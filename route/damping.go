@@ -0,0 +1,108 @@
+package route
+
+import (
+	"math"
+	"time"
+)
+
+// DampingConfig configures route flap damping as in RFC2439
+type DampingConfig struct {
+	// ChangePenalty is added for every change of the path
+	ChangePenalty float64
+	// FlapPenalty is added on top of ChangePenalty for every flap (A -> B -> A)
+	FlapPenalty float64
+	// SuppressThreshold is the penalty at which a path gets suppressed
+	SuppressThreshold float64
+	// ReuseThreshold is the penalty below which a suppressed path is reused
+	ReuseThreshold float64
+	// HalfLife is the time after which the penalty is halved
+	HalfLife time.Duration
+	// MaxSuppressTime is the maximum time a path is suppressed. Zero means no limit.
+	MaxSuppressTime time.Duration
+}
+
+// DefaultDampingConfig returns the commonly used default damping parameters
+func DefaultDampingConfig() DampingConfig {
+	return DampingConfig{
+		ChangePenalty:     500,
+		FlapPenalty:       500,
+		SuppressThreshold: 2000,
+		ReuseThreshold:    750,
+		HalfLife:          15 * time.Minute,
+		MaxSuppressTime:   60 * time.Minute,
+	}
+}
+
+// ShouldDamp checks if a path with the given history is to be suppressed at now. The penalty is replayed
+// from the recent changes of history using cfg only: Every change adds ChangePenalty (plus FlapPenalty for
+// a flap) and the penalty decays with HalfLife. A path whose penalty reached the suppress threshold stays
+// suppressed until its penalty decayed below the reuse threshold or MaxSuppressTime passed since it got
+// suppressed.
+func ShouldDamp(history ChurnStats, cfg DampingConfig, now time.Time) bool {
+	s := replayDamping(history.Recent, cfg)
+	return s.suppressed && !s.reusable(cfg, now)
+}
+
+type dampingState struct {
+	penalty      float64
+	at           time.Time
+	suppressed   bool
+	suppressedAt time.Time
+}
+
+// replayDamping accrues the penalty of events. The penalty is capped at the value that decays to the
+// reuse threshold within MaxSuppressTime (RFC2439 4.2).
+func replayDamping(events []ChurnEvent, cfg DampingConfig) dampingState {
+	s := dampingState{}
+	for _, e := range events {
+		if s.suppressed && s.reusable(cfg, e.At) {
+			s.suppressed = false
+			s.suppressedAt = time.Time{}
+		}
+
+		penalty := cfg.ChangePenalty
+		if e.Flap {
+			penalty += cfg.FlapPenalty
+		}
+
+		s.penalty = s.penaltyAt(cfg, e.At) + penalty
+		if ceiling := penaltyCeiling(cfg); s.penalty > ceiling {
+			s.penalty = ceiling
+		}
+		s.at = e.At
+
+		if !s.suppressed && s.penalty >= cfg.SuppressThreshold {
+			s.suppressed = true
+			s.suppressedAt = e.At
+		}
+	}
+
+	return s
+}
+
+// penaltyAt gets the penalty of s decayed until now
+func (s *dampingState) penaltyAt(cfg DampingConfig, now time.Time) float64 {
+	elapsed := now.Sub(s.at)
+	if s.penalty == 0 || elapsed <= 0 || cfg.HalfLife <= 0 {
+		return s.penalty
+	}
+
+	return s.penalty * math.Exp2(-elapsed.Seconds()/cfg.HalfLife.Seconds())
+}
+
+// reusable checks if a suppression ended until now
+func (s *dampingState) reusable(cfg DampingConfig, now time.Time) bool {
+	if cfg.MaxSuppressTime > 0 && now.Sub(s.suppressedAt) >= cfg.MaxSuppressTime {
+		return true
+	}
+
+	return s.penaltyAt(cfg, now) < cfg.ReuseThreshold
+}
+
+func penaltyCeiling(cfg DampingConfig) float64 {
+	if cfg.MaxSuppressTime <= 0 || cfg.HalfLife <= 0 {
+		return math.Inf(1)
+	}
+
+	return cfg.ReuseThreshold * math.Exp2(cfg.MaxSuppressTime.Seconds()/cfg.HalfLife.Seconds())
+}
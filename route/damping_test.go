@@ -0,0 +1,153 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func flapEvents(from time.Time, n int, interval time.Duration) []ChurnEvent {
+	ret := make([]ChurnEvent, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, ChurnEvent{
+			At:   from.Add(time.Duration(i) * interval),
+			Flap: true,
+		})
+	}
+
+	return ret
+}
+
+func TestShouldDamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	noMaxSuppressTime := DefaultDampingConfig()
+	noMaxSuppressTime.MaxSuppressTime = 0
+	highSuppressThreshold := DefaultDampingConfig()
+	highSuppressThreshold.SuppressThreshold = 3000
+
+	// Change (500), flap (1000), flap (1000) within seconds
+	flapping := func(at time.Time) []ChurnEvent {
+		return []ChurnEvent{
+			{At: at.Add(-2 * time.Second)},
+			{At: at.Add(-time.Second), Flap: true},
+			{At: at, Flap: true},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		events   []ChurnEvent
+		cfg      DampingConfig
+		expected bool
+	}{
+		{
+			name:     "No changes",
+			cfg:      DefaultDampingConfig(),
+			expected: false,
+		},
+		{
+			name: "Below suppress threshold",
+			events: []ChurnEvent{
+				{At: now.Add(-time.Second)},
+				{At: now, Flap: true},
+			},
+			cfg:      DefaultDampingConfig(),
+			expected: false,
+		},
+		{
+			name:     "Suppressed",
+			events:   flapping(now),
+			cfg:      DefaultDampingConfig(),
+			expected: true,
+		},
+		{
+			name:     "Same history, higher suppress threshold",
+			events:   flapping(now),
+			cfg:      highSuppressThreshold,
+			expected: false,
+		},
+		{
+			name:     "Decayed to 1250 after one half life, above reuse threshold",
+			events:   flapping(now.Add(-15 * time.Minute)),
+			cfg:      DefaultDampingConfig(),
+			expected: true,
+		},
+		{
+			name:     "Decayed below reuse threshold after two half lives",
+			events:   flapping(now.Add(-30 * time.Minute)),
+			cfg:      DefaultDampingConfig(),
+			expected: false,
+		},
+		{
+			name:     "Max suppress time since suppression exceeded",
+			events:   flapEvents(now.Add(-70*time.Minute), 61, time.Minute),
+			cfg:      DefaultDampingConfig(),
+			expected: false,
+		},
+		{
+			name:     "Still flapping without max suppress time",
+			events:   flapEvents(now.Add(-70*time.Minute), 61, time.Minute),
+			cfg:      noMaxSuppressTime,
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ShouldDamp(ChurnStats{Recent: test.events}, test.cfg, now))
+		})
+	}
+}
+
+func TestDampingFlapSuppressAndReuse(t *testing.T) {
+	cfg := DefaultDampingConfig()
+	ts := time.Unix(1700000000, 0)
+
+	c := NewChurnTracker()
+	c.now = func() time.Time {
+		return ts
+	}
+
+	a := churnPath(1, 100)
+	b := churnPath(2, 100)
+
+	c.Record(a)
+	assert.False(t, ShouldDamp(c.Stats(), cfg, ts))
+
+	// Change (500), flap (1000), flap (1000) within seconds
+	c.Record(b)
+	ts = ts.Add(time.Second)
+	c.Record(a)
+	assert.False(t, ShouldDamp(c.Stats(), cfg, ts))
+
+	ts = ts.Add(time.Second)
+	c.Record(b)
+	assert.True(t, ShouldDamp(c.Stats(), cfg, ts))
+	assert.InDelta(t, 2500, replayDamping(c.Stats().Recent, cfg).penalty, 2)
+
+	// Hysteresis: Below the suppress threshold but above the reuse threshold the path stays suppressed
+	assert.True(t, ShouldDamp(c.Stats(), cfg, ts.Add(15*time.Minute)))
+
+	// Decayed below the reuse threshold after two half lives
+	assert.False(t, ShouldDamp(c.Stats(), cfg, ts.Add(30*time.Minute)))
+
+	// Old changes are forgotten: A single change after decay does not suppress again
+	ts = ts.Add(3 * time.Hour)
+	c.Record(a)
+	assert.False(t, ShouldDamp(c.Stats(), cfg, ts))
+	assert.InDelta(t, 1000, replayDamping(c.Stats().Recent, cfg).penalty, 20)
+}
+
+func TestDampingPenaltyCeiling(t *testing.T) {
+	cfg := DefaultDampingConfig()
+	now := time.Unix(1700000000, 0)
+	history := ChurnStats{
+		Recent: flapEvents(now, 100, 0),
+	}
+
+	// 750 * 2^(60/15)
+	assert.Equal(t, float64(12000), replayDamping(history.Recent, cfg).penalty)
+	assert.True(t, ShouldDamp(history, cfg, now.Add(59*time.Minute)))
+	assert.False(t, ShouldDamp(history, cfg, now.Add(60*time.Minute)))
+}
@@ -0,0 +1,59 @@
+package route
+
+import (
+	bnet "github.com/bio-routing/bio-rd/net"
+)
+
+// ResolvedNH is the result of a recursive next hop resolution
+type ResolvedNH struct {
+	NextHop bnet.IP // Next hop of the path the resolved address is reachable via
+	Metric  uint32  // IGP metric towards the resolved address
+}
+
+// NextHopResolver resolves BGP next hops, e.g. using the IGP
+type NextHopResolver interface {
+	Resolve(bnet.IP) (*ResolvedNH, bool)
+}
+
+// NextHopReachableFunc adapts r to be used with NextHopReachable and BGPSelectOptions.NextHopResolver
+func NextHopReachableFunc(r NextHopResolver) func(bnet.IP) bool {
+	return func(addr bnet.IP) bool {
+		_, ok := r.Resolve(addr)
+		return ok
+	}
+}
+
+// EffectiveNextHopFunc adapts r to be used with EffectiveNextHop
+func EffectiveNextHopFunc(r NextHopResolver) func(bnet.IP) (*bnet.IP, bool) {
+	return func(addr bnet.IP) (*bnet.IP, bool) {
+		res, ok := r.Resolve(addr)
+		if !ok || res == nil {
+			return nil, false
+		}
+
+		return res.NextHop.Ptr(), true
+	}
+}
+
+// ResolveNextHop resolves the next hop of b using r and records the resolved next hop and the
+// IGP metric towards it on b. Returns false if the next hop could not be resolved.
+func (b *BGPPath) ResolveNextHop(r NextHopResolver) bool {
+	if r == nil || b.BGPPathA.NextHop == nil {
+		return false
+	}
+
+	res, ok := r.Resolve(*b.BGPPathA.NextHop)
+	if !ok || res == nil {
+		return false
+	}
+
+	b.ResolvedNextHop = res.NextHop.Ptr()
+	if b.BGPPathA.IGPMetric != res.Metric {
+		// BGPPathA might be shared (see Dedup)
+		a := *b.BGPPathA
+		a.IGPMetric = res.Metric
+		b.BGPPathA = &a
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNextHopResolver struct {
+	routes map[bnet.IP]ResolvedNH
+}
+
+func (f *fakeNextHopResolver) Resolve(addr bnet.IP) (*ResolvedNH, bool) {
+	res, ok := f.routes[addr]
+	if !ok {
+		return nil, false
+	}
+
+	return &res, true
+}
+
+func TestNextHopResolverFuncs(t *testing.T) {
+	reachable := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+		},
+	}
+
+	unreachable := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+	}
+
+	// Without any resolver
+	assert.True(t, reachable.NextHopReachable(nil))
+	nh, ok := reachable.EffectiveNextHop(nil)
+	assert.False(t, ok)
+	assert.Nil(t, nh)
+
+	r := &fakeNextHopResolver{
+		routes: map[bnet.IP]ResolvedNH{
+			bnet.IPv4FromOctets(10, 0, 0, 1): {
+				NextHop: bnet.IPv4FromOctets(192, 168, 0, 1),
+				Metric:  10,
+			},
+		},
+	}
+
+	assert.True(t, reachable.NextHopReachable(NextHopReachableFunc(r)))
+	assert.False(t, unreachable.NextHopReachable(NextHopReachableFunc(r)))
+
+	nh, ok = reachable.EffectiveNextHop(EffectiveNextHopFunc(r))
+	assert.True(t, ok)
+	assert.Equal(t, bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), nh)
+
+	nh, ok = unreachable.EffectiveNextHop(EffectiveNextHopFunc(r))
+	assert.False(t, ok)
+	assert.Nil(t, nh)
+
+	// A resolver only affects the call it is passed to
+	assert.True(t, unreachable.NextHopReachable(nil))
+}
+
+func TestResolveNextHop(t *testing.T) {
+	r := &fakeNextHopResolver{
+		routes: map[bnet.IP]ResolvedNH{
+			bnet.IPv4FromOctets(10, 0, 0, 1): {
+				NextHop: bnet.IPv4FromOctets(192, 168, 0, 1),
+				Metric:  10,
+			},
+		},
+	}
+
+	a := &BGPPathA{
+		NextHop: bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+	}
+	p := &BGPPath{
+		BGPPathA: a,
+	}
+
+	assert.True(t, p.ResolveNextHop(r))
+	assert.Equal(t, uint32(10), p.BGPPathA.IGPMetric)
+	assert.Equal(t, bnet.IPv4FromOctets(192, 168, 0, 1).Ptr(), p.ResolvedNextHop)
+	assert.Equal(t, uint32(0), a.IGPMetric, "shared BGPPathA must not be modified")
+
+	q := &BGPPath{
+		BGPPathA: &BGPPathA{
+			NextHop: bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+		},
+	}
+	assert.False(t, q.ResolveNextHop(r))
+	assert.Equal(t, uint32(0), q.BGPPathA.IGPMetric)
+}
@@ -47,6 +47,29 @@ func (p *Path) Select(q *Path) int8 {
 	return 0
 }
 
+// SelectWithOptions works like Select but compares BGP paths using opts (see BGPPath.SelectWithOptions)
+func (p *Path) SelectWithOptions(q *Path, opts BGPSelectOptions) int8 {
+	if p != nil && q != nil && p.Type == BGPPathType && q.Type == BGPPathType {
+		return p.BGPPath.SelectWithOptions(q.BGPPath, opts)
+	}
+
+	return p.Select(q)
+}
+
+// Eligible checks if path p may take part in path selection
+func (p *Path) Eligible() bool {
+	return p.EligibleWithOptions(BGPSelectOptions{})
+}
+
+// EligibleWithOptions works like Eligible but checks BGP paths using opts (see BGPPath.Usable)
+func (p *Path) EligibleWithOptions(opts BGPSelectOptions) bool {
+	if p.Type == BGPPathType {
+		return p.BGPPath.Usable(opts)
+	}
+
+	return true
+}
+
 // ECMP checks if path p and q are equal enough to be considered for ECMP usage
 func (p *Path) ECMP(q *Path) bool {
 	switch p.Type {
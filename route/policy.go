@@ -0,0 +1,192 @@
+package route
+
+import (
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+)
+
+// PolicyResult is the result of a policy or a policy term
+type PolicyResult uint8
+
+const (
+	// PolicyNext continues with the next term
+	PolicyNext PolicyResult = iota
+	// PolicyAccept accepts the path
+	PolicyAccept
+	// PolicyReject rejects the path
+	PolicyReject
+)
+
+// String returns the name of the policy result
+func (r PolicyResult) String() string {
+	switch r {
+	case PolicyNext:
+		return "next"
+	case PolicyAccept:
+		return "accept"
+	case PolicyReject:
+		return "reject"
+	}
+
+	return "unknown"
+}
+
+// Policy is a declarative routing policy. Terms are evaluated in order. If no term accepts or rejects
+// the path, it is accepted if DefaultAccept is set and rejected otherwise.
+type Policy struct {
+	Terms         []PolicyTerm
+	DefaultAccept bool
+}
+
+// PolicyTerm applies Set and returns Result if the path matches Match
+type PolicyTerm struct {
+	Match  PolicyMatch
+	Set    SetActions
+	Result PolicyResult
+}
+
+// PolicyMatch defines the conditions of a term. All conditions set must match.
+// An empty PolicyMatch matches all paths.
+type PolicyMatch struct {
+	// Communities matches if the path carries any of the communities or large communities
+	Communities *CommunityList
+
+	// ASPathACL matches if the AS path is permitted by the access list
+	ASPathACL ASPathACL
+}
+
+// SetActions are modifications of the path attributes. Only non nil/non empty actions are applied.
+type SetActions struct {
+	LocalPref           *uint32
+	MED                 *uint32
+	NextHop             *bnet.IP
+	AddCommunities      []uint32
+	AddLargeCommunities []types.LargeCommunity
+	PrependASN          uint32
+	PrependTimes        uint16
+}
+
+// SimulatePolicy applies p to a copy of the path and returns the copy and the result. b is not modified.
+func (b *BGPPath) SimulatePolicy(p Policy) (*BGPPath, PolicyResult) {
+	cp := b.Copy()
+	pa := *b.BGPPathA
+	cp.BGPPathA = &pa
+
+	for _, t := range p.Terms {
+		if !cp.matches(t.Match) {
+			continue
+		}
+
+		cp.applySetActions(t.Set)
+		if t.Result != PolicyNext {
+			return cp, t.Result
+		}
+	}
+
+	if p.DefaultAccept {
+		return cp, PolicyAccept
+	}
+
+	return cp, PolicyReject
+}
+
+// DryRunSet returns the attribute changes applying actions to b would cause. b is not modified.
+func (b *BGPPath) DryRunSet(actions SetActions) []AttributeDiff {
+	cp := b.Copy()
+	pa := *b.BGPPathA
+	cp.BGPPathA = &pa
+
+	cp.applySetActions(actions)
+	return b.Diff(cp)
+}
+
+func (b *BGPPath) matches(m PolicyMatch) bool {
+	if m.Communities != nil && !b.hasAnyCommunity(*m.Communities) {
+		return false
+	}
+
+	if m.ASPathACL != nil && !b.EvaluateASPathACL(m.ASPathACL) {
+		return false
+	}
+
+	return true
+}
+
+// MatchesAdvertiseMap checks if the path matches the advertise map of a conditional advertisement, i.e. it carries
+// any of the communities or large communities of m. An empty list matches all paths. Whether the condition
+// (presence or absence of another prefix) is met has to be checked by the caller.
+func (b *BGPPath) MatchesAdvertiseMap(m CommunityList) bool {
+	if len(m.Communities) == 0 && len(m.LargeCommunities) == 0 && len(m.ExtendedCommunities) == 0 {
+		return true
+	}
+
+	return b.hasAnyCommunity(m)
+}
+
+func (b *BGPPath) hasAnyCommunity(l CommunityList) bool {
+	if b.Communities != nil {
+		for _, com := range *b.Communities {
+			if l.ContainsCommunity(com) {
+				return true
+			}
+		}
+	}
+
+	if b.LargeCommunities != nil {
+		for _, com := range *b.LargeCommunities {
+			if l.ContainsLargeCommunity(com) {
+				return true
+			}
+		}
+	}
+
+	if b.ExtendedCommunities != nil {
+		for _, com := range *b.ExtendedCommunities {
+			if l.ContainsExtendedCommunity(com) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// applySetActions modifies b in place. b must not share its BGPPathA or attributes with other paths.
+func (b *BGPPath) applySetActions(s SetActions) {
+	if s.LocalPref != nil {
+		b.BGPPathA.LocalPref = *s.LocalPref
+	}
+
+	if s.MED != nil {
+		b.BGPPathA.MED = *s.MED
+		b.BGPPathA.HasMED = true
+	}
+
+	if s.NextHop != nil {
+		b.BGPPathA.NextHop = s.NextHop
+	}
+
+	if len(s.AddCommunities) > 0 {
+		if b.Communities == nil {
+			b.Communities = &types.Communities{}
+		}
+
+		*b.Communities = append(*b.Communities, s.AddCommunities...)
+	}
+
+	if len(s.AddLargeCommunities) > 0 {
+		if b.LargeCommunities == nil {
+			b.LargeCommunities = &types.LargeCommunities{}
+		}
+
+		*b.LargeCommunities = append(*b.LargeCommunities, s.AddLargeCommunities...)
+	}
+
+	if s.PrependTimes > 0 {
+		if b.ASPath == nil {
+			b.ASPath = &types.ASPath{}
+		}
+
+		b.Prepend(s.PrependASN, s.PrependTimes)
+	}
+}
@@ -0,0 +1,188 @@
+package route
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulatePolicy(t *testing.T) {
+	localPref := uint32(200)
+	policy := Policy{
+		Terms: []PolicyTerm{
+			{
+				Match: PolicyMatch{
+					Communities: &CommunityList{
+						Communities: []uint32{65000<<16 + 666},
+					},
+				},
+				Result: PolicyReject,
+			},
+			{
+				Match: PolicyMatch{
+					Communities: &CommunityList{
+						Communities: []uint32{65000<<16 + 200},
+					},
+				},
+				Set: SetActions{
+					LocalPref:      &localPref,
+					AddCommunities: []uint32{65000<<16 + 1},
+				},
+				Result: PolicyAccept,
+			},
+		},
+	}
+
+	newPath := func(coms ...uint32) *BGPPath {
+		c := types.Communities(coms)
+		return &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+			},
+			Communities: &c,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		path           *BGPPath
+		expectedPath   *BGPPath
+		expectedResult PolicyResult
+	}{
+		{
+			name: "Community matches, local pref set and accepted",
+			path: newPath(65000<<16 + 200),
+			expectedPath: &BGPPath{
+				BGPPathA: &BGPPathA{
+					LocalPref: 200,
+					NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				},
+				Communities: &types.Communities{65000<<16 + 200, 65000<<16 + 1},
+			},
+			expectedResult: PolicyAccept,
+		},
+		{
+			name:           "Rejected by first term",
+			path:           newPath(65000<<16+666, 65000<<16+200),
+			expectedPath:   newPath(65000<<16+666, 65000<<16+200),
+			expectedResult: PolicyReject,
+		},
+		{
+			name:           "No term matches, default reject",
+			path:           newPath(65000<<16 + 1),
+			expectedPath:   newPath(65000<<16 + 1),
+			expectedResult: PolicyReject,
+		},
+	}
+
+	for _, test := range tests {
+		orig := test.path.Copy()
+		res, result := test.path.SimulatePolicy(policy)
+		assert.Equal(t, test.expectedResult, result, test.name)
+		assert.Equal(t, test.expectedPath, res, test.name)
+		assert.Equal(t, orig, test.path, test.name)
+	}
+}
+
+func TestSimulatePolicyDefaultAccept(t *testing.T) {
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{},
+		ASPath: &types.ASPath{
+			{
+				Type: types.ASSequence,
+				ASNs: []uint32{3320},
+			},
+		},
+	}
+
+	res, result := p.SimulatePolicy(Policy{
+		Terms: []PolicyTerm{
+			{
+				Set: SetActions{
+					PrependASN:   201701,
+					PrependTimes: 2,
+				},
+			},
+		},
+		DefaultAccept: true,
+	})
+
+	assert.Equal(t, PolicyAccept, result)
+	assert.Equal(t, []uint32{201701, 201701, 3320}, (*res.ASPath)[0].ASNs)
+	assert.Equal(t, []uint32{3320}, (*p.ASPath)[0].ASNs)
+}
+
+func TestSimulatePolicySetMED(t *testing.T) {
+	med := uint32(0)
+	p := &BGPPath{
+		BGPPathA: &BGPPathA{},
+	}
+
+	res, _ := p.SimulatePolicy(Policy{
+		Terms: []PolicyTerm{
+			{
+				Set: SetActions{
+					MED: &med,
+				},
+			},
+		},
+		DefaultAccept: true,
+	})
+
+	assert.True(t, res.BGPPathA.MEDPresent())
+	assert.False(t, p.BGPPathA.MEDPresent())
+}
+
+func TestMatchesAdvertiseMap(t *testing.T) {
+	p := &BGPPath{
+		Communities: &types.Communities{65000<<16 | 100},
+		LargeCommunities: &types.LargeCommunities{
+			{GlobalAdministrator: 65000, DataPart1: 1, DataPart2: 2},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		m        CommunityList
+		expected bool
+	}{
+		{
+			name:     "Empty list",
+			m:        CommunityList{},
+			expected: true,
+		},
+		{
+			name: "Community matches",
+			m: CommunityList{
+				Communities: []uint32{65000<<16 | 200, 65000<<16 | 100},
+			},
+			expected: true,
+		},
+		{
+			name: "Large community matches",
+			m: CommunityList{
+				LargeCommunities: []types.LargeCommunity{
+					{GlobalAdministrator: 65000, DataPart1: 1, DataPart2: 2},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "No match",
+			m: CommunityList{
+				Communities: []uint32{65000<<16 | 200},
+				LargeCommunities: []types.LargeCommunity{
+					{GlobalAdministrator: 65000, DataPart1: 1, DataPart2: 3},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, p.MatchesAdvertiseMap(test.m), test.name)
+	}
+}
@@ -34,6 +34,10 @@ type Route struct {
 	mu        sync.Mutex
 	paths     []*Path
 	ecmpPaths uint
+
+	// ineligiblePaths is the number of paths at the end of paths excluded from path selection by
+	// PathSelection, e.g. because their next hop is unusable
+	ineligiblePaths uint
 }
 
 // NewRoute generates a new route with path p
@@ -74,8 +78,9 @@ func (r *Route) Copy() *Route {
 		return nil
 	}
 	n := &Route{
-		pfx:       r.pfx,
-		ecmpPaths: r.ecmpPaths,
+		pfx:             r.pfx,
+		ecmpPaths:       r.ecmpPaths,
+		ineligiblePaths: r.ineligiblePaths,
 	}
 	n.paths = make([]*Path, len(r.paths))
 	copy(n.paths, r.paths)
@@ -108,6 +113,24 @@ func (r *Route) Paths() []*Path {
 	return ret
 }
 
+// EligiblePathCount returns the number of paths taking part in path selection. These are the first
+// paths returned by Paths, paths excluded by PathSelection (see Path.Eligible) follow them.
+func (r *Route) EligiblePathCount() uint {
+	if r == nil {
+		return 0
+	}
+
+	return r.eligiblePathCount()
+}
+
+func (r *Route) eligiblePathCount() uint {
+	if r.ineligiblePaths > uint(len(r.paths)) {
+		return 0
+	}
+
+	return uint(len(r.paths)) - r.ineligiblePaths
+}
+
 // ECMPPathCount returns the count of ecmp paths for route r
 func (r *Route) ECMPPathCount() uint {
 	if r == nil {
@@ -138,7 +161,7 @@ func (r *Route) BestPath() *Path {
 	if r == nil {
 		return nil
 	}
-	if len(r.paths) == 0 {
+	if r.eligiblePathCount() == 0 {
 		return nil
 	}
 
@@ -154,7 +177,11 @@ func (r *Route) AddPath(p *Path) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Keep the paths excluded from path selection at the end
+	n := r.eligiblePathCount()
 	r.paths = append(r.paths, p)
+	copy(r.paths[n+1:], r.paths[n:])
+	r.paths[n] = p
 }
 
 // RemovePath removes path `p` from route `r`. Returns length of path list after removing path `p`
@@ -166,6 +193,11 @@ func (r *Route) RemovePath(p *Path) int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	i := pathIndex(r.paths, p)
+	if i >= 0 && uint(i) >= r.eligiblePathCount() {
+		r.ineligiblePaths--
+	}
+
 	r.paths = removePath(r.paths, p)
 	return len(r.paths)
 }
@@ -183,14 +215,7 @@ func (r *Route) ReplacePath(old *Path, new *Path) error {
 }
 
 func removePath(paths []*Path, remove *Path) []*Path {
-	i := -1
-	for j := range paths {
-		if paths[j].Compare(remove) {
-			i = j
-			break
-		}
-	}
-
+	i := pathIndex(paths, remove)
 	if i < 0 {
 		return paths
 	}
@@ -199,13 +224,31 @@ func removePath(paths []*Path, remove *Path) []*Path {
 	return paths[:len(paths)-1]
 }
 
-// PathSelection recalculates the best path + active paths
+func pathIndex(paths []*Path, p *Path) int {
+	for i := range paths {
+		if paths[i].Compare(p) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// PathSelection recalculates the best path + active paths. Paths not eligible for path selection
+// (see Path.Eligible) are moved to the end of the list of paths and never become best or active.
 func (r *Route) PathSelection() {
+	r.PathSelectionWithOptions(BGPSelectOptions{})
+}
+
+// PathSelectionWithOptions works like PathSelection but applies opts to BGP paths
+func (r *Route) PathSelectionWithOptions(opts BGPSelectOptions) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	sort.Slice(r.paths, func(i, j int) bool {
-		return r.paths[i].Select(r.paths[j]) == 1
+	r.partitionPaths(opts)
+	eligible := r.paths[:r.eligiblePathCount()]
+	sort.Slice(eligible, func(i, j int) bool {
+		return eligible[i].SelectWithOptions(eligible[j], opts) == 1
 	})
 
 	r.updateEqualPathCount()
@@ -308,14 +351,32 @@ func RouteFromProtoRoute(ar *api.Route, dedup bool) *Route {
 	return r
 }
 
+// partitionPaths moves the paths not eligible for path selection to the end of r.paths keeping their order
+func (r *Route) partitionPaths(opts BGPSelectOptions) {
+	var ineligible []*Path
+	eligible := r.paths[:0]
+	for _, p := range r.paths {
+		if p.EligibleWithOptions(opts) {
+			eligible = append(eligible, p)
+			continue
+		}
+
+		ineligible = append(ineligible, p)
+	}
+
+	r.paths = append(eligible, ineligible...)
+	r.ineligiblePaths = uint(len(ineligible))
+}
+
 func (r *Route) updateEqualPathCount() {
-	if len(r.paths) == 0 {
+	n := int(r.eligiblePathCount())
+	if n == 0 {
 		r.ecmpPaths = 0
 		return
 	}
 
 	count := uint(1)
-	for i := 0; i < len(r.paths)-1; i++ {
+	for i := 0; i < n-1; i++ {
 		if !r.paths[i].ECMP(r.paths[i+1]) {
 			break
 		}
@@ -24,6 +24,7 @@ func TestPathSelection(t *testing.T) {
 						Type: BGPPathType,
 						BGPPath: &BGPPath{
 							BGPPathA: &BGPPathA{
+								NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 								LocalPref: 1000,
 							},
 						},
@@ -32,6 +33,7 @@ func TestPathSelection(t *testing.T) {
 						Type: BGPPathType,
 						BGPPath: &BGPPath{
 							BGPPathA: &BGPPathA{
+								NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 								LocalPref: 100,
 							},
 						},
@@ -43,6 +45,7 @@ func TestPathSelection(t *testing.T) {
 					Type: BGPPathType,
 					BGPPath: &BGPPath{
 						BGPPathA: &BGPPathA{
+							NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 							LocalPref: 1000,
 						},
 					},
@@ -51,6 +54,7 @@ func TestPathSelection(t *testing.T) {
 					Type: BGPPathType,
 					BGPPath: &BGPPath{
 						BGPPathA: &BGPPathA{
+							NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 							LocalPref: 100,
 						},
 					},
@@ -65,6 +69,7 @@ func TestPathSelection(t *testing.T) {
 						Type: BGPPathType,
 						BGPPath: &BGPPath{
 							BGPPathA: &BGPPathA{
+								NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 								LocalPref: 1000,
 							},
 							ASPathLen: 3,
@@ -74,6 +79,7 @@ func TestPathSelection(t *testing.T) {
 						Type: BGPPathType,
 						BGPPath: &BGPPath{
 							BGPPathA: &BGPPathA{
+								NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 								LocalPref: 1000,
 							},
 							ASPathLen: 1,
@@ -86,6 +92,7 @@ func TestPathSelection(t *testing.T) {
 					Type: BGPPathType,
 					BGPPath: &BGPPath{
 						BGPPathA: &BGPPathA{
+							NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 							LocalPref: 1000,
 						},
 						ASPathLen: 1,
@@ -95,6 +102,7 @@ func TestPathSelection(t *testing.T) {
 					Type: BGPPathType,
 					BGPPath: &BGPPath{
 						BGPPathA: &BGPPathA{
+							NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
 							LocalPref: 1000,
 						},
 						ASPathLen: 3,
@@ -110,6 +118,53 @@ func TestPathSelection(t *testing.T) {
 	}
 }
 
+func TestPathSelectionIneligible(t *testing.T) {
+	v4 := &Path{
+		Type: BGPPathType,
+		BGPPath: &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 100,
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				Source:    bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+			AFI:    afiIPv4,
+		},
+	}
+
+	v6NextHop := &Path{
+		Type: BGPPathType,
+		BGPPath: &BGPPath{
+			BGPPathA: &BGPPathA{
+				LocalPref: 200,
+				NextHop:   bnet.IPv6(0x20010db8<<32, 1).Ptr(),
+				Source:    bnet.IPv4(0).Ptr(),
+			},
+			ASPath: &types.ASPath{},
+			AFI:    afiIPv4,
+		},
+	}
+
+	r := NewRoute(bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24).Ptr(), v6NextHop)
+	r.PathSelection()
+	assert.Nil(t, r.BestPath())
+	assert.Equal(t, uint(0), r.ECMPPathCount())
+	assert.Equal(t, uint(0), r.EligiblePathCount())
+	assert.Equal(t, []*Path{v6NextHop}, r.Paths())
+
+	r.AddPath(v4)
+	assert.Equal(t, []*Path{v4, v6NextHop}, r.Paths())
+	r.PathSelection()
+	assert.Equal(t, v4, r.BestPath())
+	assert.Equal(t, uint(1), r.EligiblePathCount())
+	assert.Equal(t, []*Path{v4, v6NextHop}, r.Paths())
+
+	assert.Equal(t, 1, r.RemovePath(v6NextHop))
+	assert.Equal(t, uint(1), r.EligiblePathCount())
+	assert.Equal(t, v4, r.BestPath())
+	assert.Equal(t, 0, r.RemovePath(v4))
+}
+
 func TestNewRoute(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -746,3 +801,47 @@ func TestRouteIsBGPOriginatedBy(t *testing.T) {
 		assert.Equal(t, tc.isOrig, res, tc.name)
 	}
 }
+
+func TestPathSelectionWithOptionsUnreachable(t *testing.T) {
+	newPath := func(localPref uint32, nextHop bnet.IP) *Path {
+		return &Path{
+			Type: BGPPathType,
+			BGPPath: &BGPPath{
+				BGPPathA: &BGPPathA{
+					LocalPref: localPref,
+					NextHop:   nextHop.Ptr(),
+					Source:    bnet.IPv4(0).Ptr(),
+				},
+				ASPath: &types.ASPath{},
+			},
+		}
+	}
+
+	reachableNH := bnet.IPv4FromOctets(10, 0, 0, 1)
+	opts := BGPSelectOptions{
+		NextHopResolver: func(addr bnet.IP) bool {
+			return addr == reachableNH
+		},
+	}
+
+	unreachable1 := newPath(300, bnet.IPv4FromOctets(10, 0, 0, 2))
+	unreachable2 := newPath(200, bnet.IPv4FromOctets(10, 0, 0, 3))
+
+	r := NewRouteAddPath(bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24).Ptr(), []*Path{unreachable1, unreachable2})
+	r.PathSelectionWithOptions(opts)
+	assert.Nil(t, r.BestPath(), "all paths unreachable")
+	assert.Empty(t, r.ECMPPaths())
+	assert.Equal(t, []*Path{unreachable1, unreachable2}, r.Paths())
+
+	reachable := newPath(100, reachableNH)
+	r.AddPath(reachable)
+	r.PathSelectionWithOptions(opts)
+	assert.Equal(t, reachable, r.BestPath())
+	assert.Equal(t, uint(1), r.EligiblePathCount())
+	assert.Equal(t, []*Path{reachable, unreachable1, unreachable2}, r.Paths())
+
+	// Without resolver all next hops are reachable again
+	r.PathSelection()
+	assert.Equal(t, uint(3), r.EligiblePathCount())
+	assert.Equal(t, []*Path{unreachable1, unreachable2, reachable}, r.Paths())
+}
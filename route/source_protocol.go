@@ -0,0 +1,34 @@
+package route
+
+// SourceProtocol identifies the protocol a path was originally learned from
+type SourceProtocol uint8
+
+const (
+	// SourceProtocolBGP indicates a path learned via BGP
+	SourceProtocolBGP SourceProtocol = iota
+
+	// SourceProtocolConnected indicates a path redistributed from a connected network
+	SourceProtocolConnected
+
+	// SourceProtocolStatic indicates a path redistributed from a static route
+	SourceProtocolStatic
+
+	// SourceProtocolOSPF indicates a path redistributed from OSPF
+	SourceProtocolOSPF
+)
+
+// String returns the name of the protocol
+func (s SourceProtocol) String() string {
+	switch s {
+	case SourceProtocolBGP:
+		return "BGP"
+	case SourceProtocolConnected:
+		return "connected"
+	case SourceProtocolStatic:
+		return "static"
+	case SourceProtocolOSPF:
+		return "OSPF"
+	default:
+		return "unknown"
+	}
+}
@@ -73,7 +73,7 @@ func (a *AdjRIBOut) bgpChecks(pfx *bnet.Prefix, p *route.Path) (retPath *route.P
 	}
 
 	// Don't export routes learned via iBGP to an iBGP neighbor which is NOT a route reflection client
-	if !p.BGPPath.BGPPathA.EBGP && a.neighbor.IBGP && !a.neighbor.RouteReflectorClient {
+	if a.neighbor.IBGP && !p.BGPPath.AdvertisableToIBGP(a.neighbor.RouteReflectorClient) {
 		return nil, false
 	}
 
@@ -188,8 +188,14 @@ func (a *AdjRIBOut) removePath(pfx *bnet.Prefix, p *route.Path) bool {
 
 	sentPath := p
 	if a.addPathTX {
+		// The sent path carries the path identifier we allocated for its attributes
+		pathID, found := a.pathIDManager.lookup(p)
+		if !found {
+			return false
+		}
+
 		for _, sp := range r.Paths() {
-			if sp.Select(p) == 0 {
+			if sp.BGPPath.PathIdentifier == pathID && sp.BGPPath.AttributesEqual(p.BGPPath) {
 				a.rt.RemovePath(pfx, sp)
 
 				_, err := a.pathIDManager.releasePath(p)
@@ -1574,3 +1574,50 @@ func TestAddPathIBGP(t *testing.T) {
 		}
 	}
 }
+
+func TestAddPathRemoveIgnoresIGPMetric(t *testing.T) {
+	neighbor := &routingtable.Neighbor{
+		Type:         route.BGPPathType,
+		LocalAddress: net.IPv4FromOctets(127, 0, 0, 1).Ptr(),
+		Address:      net.IPv4FromOctets(127, 0, 0, 2).Ptr(),
+		IBGP:         true,
+		LocalASN:     41981,
+	}
+
+	adjRIBOut := New(nil, neighbor, filter.NewAcceptAllFilterChain(), true)
+	client := routingtable.NewRTMockClient()
+	adjRIBOut.Register(client)
+
+	newPath := func(pathID uint32, igpMetric uint32) *route.Path {
+		return &route.Path{
+			Type: route.BGPPathType,
+			BGPPath: &route.BGPPath{
+				BGPPathA: &route.BGPPathA{
+					EBGP:      true,
+					Source:    net.IPv4(0).Ptr(),
+					NextHop:   net.IPv4FromOctets(1, 2, 3, 4).Ptr(),
+					IGPMetric: igpMetric,
+				},
+				ASPath: &types.ASPath{
+					{
+						Type: types.ASSequence,
+						ASNs: []uint32{201701},
+					},
+				},
+				ASPathLen:      1,
+				PathIdentifier: pathID,
+			},
+		}
+	}
+
+	pfx := net.NewPfx(net.IPv4FromOctets(10, 0, 0, 0), 8)
+	assert.NoError(t, adjRIBOut.AddPath(pfx.Ptr(), newPath(100, 10)))
+	assert.Equal(t, int64(1), adjRIBOut.RouteCount())
+
+	// The IGP metric to the next hop changed since the path was sent, it is not advertised
+	assert.True(t, adjRIBOut.RemovePath(pfx.Ptr(), newPath(100, 20)))
+	assert.Equal(t, int64(0), adjRIBOut.RouteCount())
+	if assert.Len(t, client.Removed(), 1) {
+		assert.Equal(t, uint32(1), client.Removed()[0].Path.BGPPath.PathIdentifier)
+	}
+}
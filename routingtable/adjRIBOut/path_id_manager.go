@@ -52,6 +52,12 @@ func (fm *pathIDManager) addPath(p *route.Path) (uint32, error) {
 	return fm.last, nil
 }
 
+// lookup gets the path ID allocated for a path with the attributes of p
+func (fm *pathIDManager) lookup(p *route.Path) (uint32, bool) {
+	id, exists := fm.idByPath[p.BGPPath.ComputeHash()]
+	return id, exists
+}
+
 func (fm *pathIDManager) releasePath(p *route.Path) (uint32, error) {
 	hash := p.BGPPath.ComputeHash()
 
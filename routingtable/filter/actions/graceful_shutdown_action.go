@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/route"
+)
+
+// GracefulShutdownAction sets the LOCAL_PREF of paths carrying the GRACEFUL_SHUTDOWN community to 0 (RFC8326)
+type GracefulShutdownAction struct{}
+
+// NewGracefulShutdownAction creates a new GracefulShutdownAction
+func NewGracefulShutdownAction() *GracefulShutdownAction {
+	return &GracefulShutdownAction{}
+}
+
+// Do applies the action
+func (a *GracefulShutdownAction) Do(p *net.Prefix, pa *route.Path) Result {
+	if pa.BGPPath == nil || !pa.BGPPath.HasGracefulShutdown() {
+		return Result{Path: pa}
+	}
+
+	modified := pa.Copy()
+	bgpPathA := *modified.BGPPath.BGPPathA
+	bgpPathA.LocalPref = 0
+	modified.BGPPath.BGPPathA = &bgpPathA
+
+	return Result{Path: modified}
+}
+
+// Equal compares actions
+func (a *GracefulShutdownAction) Equal(b Action) bool {
+	switch b.(type) {
+	case *GracefulShutdownAction:
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package actions
+
+import (
+	"testing"
+
+	bnet "github.com/bio-routing/bio-rd/net"
+	"github.com/bio-routing/bio-rd/protocols/bgp/types"
+	"github.com/bio-routing/bio-rd/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name              string
+		bgpPath           *route.BGPPath
+		expectedLocalPref uint32
+	}{
+		{
+			name: "BGPPath is nil",
+		},
+		{
+			name: "community present",
+			bgpPath: &route.BGPPath{
+				BGPPathA: &route.BGPPathA{
+					LocalPref: 100,
+				},
+				Communities: &types.Communities{65000<<16 + 1, types.WellKnownCommunityGracefulShutdown},
+			},
+			expectedLocalPref: 0,
+		},
+		{
+			name: "community not present",
+			bgpPath: &route.BGPPath{
+				BGPPathA: &route.BGPPathA{
+					LocalPref: 100,
+				},
+				Communities: &types.Communities{65000<<16 + 1},
+			},
+			expectedLocalPref: 100,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := NewGracefulShutdownAction()
+			res := a.Do(bnet.NewPfx(bnet.IPv4FromOctets(10, 0, 0, 0), 8).Ptr(), &route.Path{
+				BGPPath: test.bgpPath,
+			})
+
+			if test.bgpPath != nil {
+				assert.Equal(t, test.expectedLocalPref, res.Path.BGPPath.BGPPathA.LocalPref)
+			}
+		})
+	}
+}
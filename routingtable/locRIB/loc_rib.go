@@ -20,6 +20,7 @@ type LocRIB struct {
 	mu               sync.RWMutex
 	contributingASNs *routingtable.ContributingASNs
 	countTarget      *countTarget
+	bgpSelectOptions route.BGPSelectOptions
 }
 
 type countTarget struct {
@@ -29,10 +30,16 @@ type countTarget struct {
 
 // New creates a new routing information base
 func New(name string) *LocRIB {
+	return NewWithOptions(name, route.BGPSelectOptions{})
+}
+
+// NewWithOptions creates a new routing information base applying opts to the BGP best path selection
+func NewWithOptions(name string, opts route.BGPSelectOptions) *LocRIB {
 	a := &LocRIB{
 		name:             name,
 		rt:               routingtable.NewRoutingTable(),
 		contributingASNs: routingtable.NewContributingASNs(),
+		bgpSelectOptions: opts,
 	}
 	a.clientManager = routingtable.NewClientManager(a)
 
@@ -106,9 +113,11 @@ func (a *LocRIB) UpdateNewClient(client routingtable.RouteTableClient) error {
 			n = r.ECMPPathCount()
 		} else {
 			n = opts.MaxPaths
-			n = uint(math.Min(int(n), len(r.Paths())))
 		}
 
+		// Paths excluded from path selection are never propagated
+		n = uint(math.Min(int(n), int(r.EligiblePathCount())))
+
 		for _, p := range r.Paths()[:n] {
 			client.AddPathInitialDump(r.Prefix(), p)
 		}
@@ -133,9 +142,11 @@ func (a *LocRIB) RefreshClient(client routingtable.RouteTableClient) {
 			n = r.ECMPPathCount()
 		} else {
 			n = opts.MaxPaths
-			n = uint(math.Min(int(n), len(r.Paths())))
 		}
 
+		// Paths excluded from path selection are never propagated
+		n = uint(math.Min(int(n), int(r.EligiblePathCount())))
+
 		client.RefreshRoute(r.Prefix(), r.Paths()[:n])
 	}
 }
@@ -171,7 +182,7 @@ func (a *LocRIB) AddPath(pfx *net.Prefix, p *route.Path) error {
 		r = a.rt.Get(pfx)
 	}
 
-	r.PathSelection()
+	r.PathSelectionWithOptions(a.bgpSelectOptions)
 	newRoute := r.Copy()
 
 	a.propagateChanges(oldRoute, newRoute)
@@ -201,7 +212,7 @@ func (a *LocRIB) RemovePath(pfx *net.Prefix, p *route.Path) bool {
 	}
 
 	a.rt.RemovePath(pfx, p)
-	r.PathSelection()
+	r.PathSelectionWithOptions(a.bgpSelectOptions)
 
 	r = a.rt.Get(pfx)
 	newRoute := r.Copy()
@@ -227,7 +238,7 @@ func (a *LocRIB) ReplacePath(pfx *net.Prefix, oldPath *route.Path, newPath *rout
 		return
 	}
 
-	r.PathSelection()
+	r.PathSelectionWithOptions(a.bgpSelectOptions)
 	a.propagateChanges(oldRoute, r)
 }
 
@@ -242,8 +253,8 @@ func (a *LocRIB) addPathsToClients(oldRoute *route.Route, newRoute *route.Route)
 		oldMaxPaths := opts.GetMaxPaths(oldRoute.ECMPPathCount())
 		newMaxPaths := opts.GetMaxPaths(newRoute.ECMPPathCount())
 
-		oldPathsLimit := int(math.Min(int(oldMaxPaths), len(oldRoute.Paths())))
-		newPathsLimit := int(math.Min(int(newMaxPaths), len(newRoute.Paths())))
+		oldPathsLimit := int(math.Min(int(oldMaxPaths), int(oldRoute.EligiblePathCount())))
+		newPathsLimit := int(math.Min(int(newMaxPaths), int(newRoute.EligiblePathCount())))
 
 		advertise := route.PathsDiff(newRoute.Paths()[0:newPathsLimit], oldRoute.Paths()[0:oldPathsLimit])
 
@@ -259,8 +270,8 @@ func (a *LocRIB) removePathsFromClients(oldRoute *route.Route, newRoute *route.R
 		oldMaxPaths := opts.GetMaxPaths(oldRoute.ECMPPathCount())
 		newMaxPaths := opts.GetMaxPaths(newRoute.ECMPPathCount())
 
-		oldPathsLimit := int(math.Min(int(oldMaxPaths), len(oldRoute.Paths())))
-		newPathsLimit := int(math.Min(int(newMaxPaths), len(newRoute.Paths())))
+		oldPathsLimit := int(math.Min(int(oldMaxPaths), int(oldRoute.EligiblePathCount())))
+		newPathsLimit := int(math.Min(int(newMaxPaths), int(newRoute.EligiblePathCount())))
 
 		withdraw := route.PathsDiff(oldRoute.Paths()[0:oldPathsLimit], newRoute.Paths()[0:newPathsLimit])
 
@@ -5,6 +5,7 @@ import (
 
 	bnet "github.com/bio-routing/bio-rd/net"
 	"github.com/bio-routing/bio-rd/route"
+	"github.com/bio-routing/bio-rd/routingtable"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -97,3 +98,64 @@ func TestLocRIB_RemovePathUnknown(t *testing.T) {
 				},
 			}))
 }
+
+func TestLocRIBUnreachableNextHop(t *testing.T) {
+	rib := NewWithOptions("inet.0", route.BGPSelectOptions{
+		NextHopResolver: func(addr bnet.IP) bool {
+			return addr == bnet.IPv4FromOctets(10, 0, 0, 1)
+		},
+	})
+
+	pfx := bnet.NewPfx(bnet.IPv4FromOctets(192, 0, 2, 0), 24)
+	unreachable := &route.Path{
+		Type: route.BGPPathType,
+		BGPPath: &route.BGPPath{
+			BGPPathA: &route.BGPPathA{
+				LocalPref: 200,
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 2).Ptr(),
+				Source:    bnet.IPv4(0).Ptr(),
+			},
+		},
+	}
+	reachable := &route.Path{
+		Type: route.BGPPathType,
+		BGPPath: &route.BGPPath{
+			BGPPathA: &route.BGPPathA{
+				LocalPref: 100,
+				NextHop:   bnet.IPv4FromOctets(10, 0, 0, 1).Ptr(),
+				Source:    bnet.IPv4(0).Ptr(),
+			},
+		},
+	}
+
+	client := &addPathRecorder{
+		RTMockClient: routingtable.NewRTMockClient(),
+	}
+	rib.RegisterWithOptions(client, routingtable.ClientOptions{
+		MaxPaths: 10,
+	})
+
+	assert.NoError(t, rib.AddPath(&pfx, unreachable))
+	assert.Nil(t, rib.rt.Get(&pfx).BestPath())
+	assert.Empty(t, client.added)
+
+	assert.NoError(t, rib.AddPath(&pfx, reachable))
+	assert.Equal(t, reachable, rib.rt.Get(&pfx).BestPath())
+	assert.Equal(t, []*route.Path{reachable}, client.added)
+	assert.Equal(t, []*route.Path{reachable, unreachable}, rib.rt.Get(&pfx).Paths())
+	assert.True(t, rib.ContainsPfxPath(&pfx, unreachable))
+
+	assert.True(t, rib.RemovePath(&pfx, unreachable))
+	assert.False(t, rib.ContainsPfxPath(&pfx, unreachable))
+	assert.Equal(t, reachable, rib.rt.Get(&pfx).BestPath())
+}
+
+type addPathRecorder struct {
+	*routingtable.RTMockClient
+	added []*route.Path
+}
+
+func (r *addPathRecorder) AddPath(pfx *bnet.Prefix, p *route.Path) error {
+	r.added = append(r.added, p)
+	return nil
+}